@@ -0,0 +1,406 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mateimicu/tmux-claude-matrix/internal/archive"
+	"github.com/mateimicu/tmux-claude-matrix/internal/git"
+	"github.com/mateimicu/tmux-claude-matrix/internal/logging"
+	"github.com/mateimicu/tmux-claude-matrix/internal/session"
+	"github.com/mateimicu/tmux-claude-matrix/internal/status"
+	"github.com/mateimicu/tmux-claude-matrix/internal/tmux"
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+func archiveCmd() *cobra.Command {
+	var output string
+	var keep bool
+
+	cmd := &cobra.Command{
+		Use:   "archive <session>",
+		Short: "Archive a session to a bundle and free its resources",
+		Long: `Write the session metadata, a git bundle of local commits and a patch of
+uncommitted changes for every repository of the session to a compressed
+bundle, then kill the tmux session and remove the clone. Use 'restore' to
+bring the session back.
+
+Stashes are not archived, so a session with stashes is only archived with
+--keep; apply or drop them first to release the session.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runArchive(cmd.Context(), args[0], output, keep)
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Bundle path (default: ARCHIVE_DIR/<session>-<timestamp>.tar.gz)")
+	cmd.Flags().BoolVar(&keep, "keep", false, "Only write the bundle, keep the session")
+
+	return cmd
+}
+
+func restoreCmd() *cobra.Command {
+	var noSwitch bool
+
+	cmd := &cobra.Command{
+		Use:   "restore <bundle>",
+		Short: "Restore an archived session",
+		Long: `Recreate an archived session: clone its repositories from the mirror cache,
+reapply local commits and uncommitted changes, register the session and
+resume the Claude conversation.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRestore(cmd.Context(), args[0], noSwitch)
+		},
+	}
+
+	cmd.Flags().BoolVar(&noSwitch, "no-switch", false, "Don't switch to the restored session")
+
+	return cmd
+}
+
+func runArchive(ctx context.Context, name, output string, keep bool) error {
+	cfg := configFromContext(ctx)
+	log := loggerFromContext(ctx)
+
+	sessionMgr := session.NewManager(cfg.SessionsDir)
+	gitMgr := git.New()
+	tmuxMgr := tmux.New()
+
+	sess, err := sessionMgr.Load(name)
+	if err != nil {
+		return fmt.Errorf("session %q not found: %w", name, err)
+	}
+	if _, err := os.Stat(sess.ClonePath); err != nil {
+		return fmt.Errorf("clone of session %q is missing: %w", name, err)
+	}
+
+	repoPaths := sessionRepoPaths(sess.ClonePath)
+	if len(repoPaths) == 0 {
+		return fmt.Errorf("no git repositories found in %s", sess.ClonePath)
+	}
+
+	// Stashes are not bundled, so removing the clone would lose them
+	for _, path := range repoPaths {
+		st, err := gitMgr.CheckRepoStatus(path)
+		if err != nil {
+			return fmt.Errorf("failed to inspect %s: %w", path, err)
+		}
+		if st.Stashes == 0 {
+			continue
+		}
+		if !keep {
+			return fmt.Errorf("%s has %d stash(es), which archives do not include; apply or drop them, or archive with --keep", path, st.Stashes)
+		}
+		log.Warnf("⚠️  %s has %d stash(es), which the archive does not include\n", path, st.Stashes)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "claude-matrix-archive-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	notes, err := sessionMgr.LoadNotes(sess.Name)
+	if err != nil {
+		return fmt.Errorf("failed to read notes: %w", err)
+	}
+
+	manifest := &archive.Manifest{ArchivedAt: time.Now(), Session: sess, Notes: notes}
+	for i, path := range repoPaths {
+		log.Debugf("📦 Archiving %s...\n", path)
+		repo, err := archiveRepo(gitMgr, sess, path, filepath.Join(tmpDir, fmt.Sprint(i)))
+		if err != nil {
+			return err
+		}
+		manifest.Repos = append(manifest.Repos, repo)
+	}
+
+	if output == "" {
+		output = filepath.Join(cfg.ArchiveDir, fmt.Sprintf("%s-%s.tar.gz", sess.Name, time.Now().Format("20060102-150405")))
+	}
+	if err := archive.Write(output, manifest); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	// User-facing success confirmation — always visible
+	fmt.Printf("✓ Session '%s' archived to %s\n", sess.Name, output)
+
+	if keep {
+		return nil
+	}
+	return releaseArchivedSession(cfg, sessionMgr, gitMgr, tmuxMgr, sess, log)
+}
+
+// archiveRepo captures the local state of the checkout at path. Bundle and
+// patch files are written next to filePrefix.
+func archiveRepo(gitMgr *git.Manager, sess *types.Session, path, filePrefix string) (*archive.Repo, error) {
+	dir, err := filepath.Rel(sess.ClonePath, path)
+	if err != nil {
+		return nil, err
+	}
+	url, err := gitMgr.RemoteURL(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read origin of %s: %w", path, err)
+	}
+	head, err := gitMgr.HeadCommit(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HEAD of %s: %w", path, err)
+	}
+	branch, _ := gitMgr.CurrentBranch(path) //nolint:errcheck // Detached HEAD leaves the branch empty
+	if branch == "" && sess.MirrorPath != "" {
+		return nil, fmt.Errorf("worktree %s has a detached HEAD; check out a branch before archiving", path)
+	}
+
+	repo := &archive.Repo{Dir: dir, URL: url, Branch: branch, Head: head}
+
+	bundle := filePrefix + ".bundle"
+	ok, err := gitMgr.CreateBundle(path, bundle)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		repo.Bundle = bundle
+	}
+
+	patch, err := gitMgr.UncommittedPatch(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(patch) > 0 {
+		repo.Patch = filePrefix + ".patch"
+		if err := os.WriteFile(repo.Patch, patch, 0644); err != nil {
+			return nil, err
+		}
+	}
+
+	return repo, nil
+}
+
+// releaseArchivedSession kills the tmux session of an archived session and
+// removes its checkout, metadata and state files. runArchive refuses to get
+// here while the checkout has stashes; everything else is in the archive, so
+// local work is discarded without asking.
+func releaseArchivedSession(cfg *types.Config, sessionMgr *session.Manager, gitMgr *git.Manager, tmuxMgr *tmux.Manager, sess *types.Session, log *logging.Logger) error {
+	if tmuxMgr.SessionExists(sess.Name) {
+		log.Debugf("🛑 Killing tmux session '%s'...\n", sess.Name)
+		if err := tmuxMgr.KillSession(sess.Name); err != nil {
+			log.Warnf("⚠️  Failed to kill tmux session: %v\n", err)
+		}
+	}
+
+	switch {
+	case sess.MirrorPath != "":
+		log.Debugf("🌿 Removing worktree '%s'...\n", sess.ClonePath)
+		if err := gitMgr.RemoveWorktree(sess.MirrorPath, sess.ClonePath, true); err != nil {
+			log.Warnf("⚠️  Failed to remove worktree: %v\n", err)
+		}
+	case isWithinDir(cfg.CloneDir, sess.ClonePath):
+		log.Debugf("📁 Removing clone directory '%s'...\n", sess.ClonePath)
+		if err := os.RemoveAll(sess.ClonePath); err != nil {
+			log.Warnf("⚠️  Failed to remove clone directory: %v\n", err)
+		}
+	default:
+		log.Warnf("⚠️  Clone path '%s' is outside %s, leaving it in place\n", sess.ClonePath, cfg.CloneDir)
+	}
+
+	if err := sessionMgr.Delete(sess.Name); err != nil {
+		return fmt.Errorf("failed to delete session metadata: %w", err)
+	}
+
+	statusDir := status.DefaultStatusDir()
+	status.RemoveAllAgentStates(statusDir, sess.Name) //nolint:errcheck // Best-effort cleanup
+	status.RemoveState(statusDir, sess.Name)          //nolint:errcheck // Best-effort cleanup
+
+	return nil
+}
+
+func runRestore(ctx context.Context, bundlePath string, noSwitch bool) (retErr error) {
+	cfg := configFromContext(ctx)
+	log := loggerFromContext(ctx)
+
+	sessionMgr := session.NewManager(cfg.SessionsDir)
+	gitMgr := git.New()
+	gitMgr.SetSessionBranchPattern(cfg.BranchPattern)
+	tmuxMgr := tmux.New()
+
+	tmpDir, err := os.MkdirTemp("", "claude-matrix-restore-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	manifest, err := archive.Read(bundlePath, tmpDir)
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+	if err := checkRestorable(cfg, manifest); err != nil {
+		return err
+	}
+	sess := manifest.Session
+
+	if tmuxMgr.SessionExists(sess.Name) {
+		return fmt.Errorf("session %q already exists", sess.Name)
+	}
+	if err := sessionMgr.Reserve(sess.Name); err != nil {
+		return err
+	}
+	defer sessionMgr.Release(sess.Name)
+	// The original path is reused so Claude can resume the conversation,
+	// which it stores per working directory
+	if _, err := os.Stat(sess.ClonePath); err == nil {
+		return fmt.Errorf("%s already exists", sess.ClonePath)
+	}
+
+	// A failed restore leaves nothing behind, so it can simply be retried
+	var targets []string
+	tmuxStarted := false
+	defer func() {
+		if retErr != nil {
+			abandonRestore(gitMgr, tmuxMgr, sess, targets, tmuxStarted, log)
+		}
+	}()
+
+	worktree := sess.MirrorPath != ""
+	for _, repo := range manifest.Repos {
+		target := filepath.Join(sess.ClonePath, repo.Dir)
+		log.Debugf("📦 Restoring %s into %s...\n", repo.URL, target)
+		targets = append(targets, target)
+
+		mirrorPath, err := restoreRepo(cfg, gitMgr, repo, target, worktree)
+		if err != nil {
+			return fmt.Errorf("failed to restore %s: %w", repo.URL, err)
+		}
+		if worktree {
+			sess.MirrorPath = mirrorPath
+		}
+	}
+
+	log.Debugf("🚀 Creating tmux session '%s'...\n", sess.Name)
+	if err := startTmuxSession(cfg, tmuxMgr, sess.Name, sess.ClonePath, buildRecreateCommand(cfg, sess), sess.Layout); err != nil {
+		return fmt.Errorf("failed to create tmux session: %w", err)
+	}
+	tmuxStarted = true
+
+	if err := sessionMgr.Save(sess); err != nil {
+		return fmt.Errorf("failed to save session metadata: %w", err)
+	}
+	if err := sessionMgr.SaveNotes(sess.Name, manifest.Notes); err != nil {
+		log.Warnf("⚠️  Failed to restore notes: %v\n", err)
+	}
+	if err := tmuxMgr.SetSessionEnv(sess.Name, "@claude-matrix-title", sess.Title); err != nil {
+		log.Warnf("⚠️  Failed to set session title env: %v\n", err)
+	}
+
+	// User-facing success confirmation — always visible
+	fmt.Printf("✓ Session restored: %s\n", sess.Name)
+
+	if noSwitch {
+		return nil
+	}
+	if err := tmuxMgr.SwitchToSession(sess.Name); err != nil {
+		log.Warnf("⚠️  Failed to switch to session: %v\n", err)
+		log.Warnf("You can attach manually with: tmux attach -t %s\n", sess.Name)
+	}
+	return nil
+}
+
+// checkRestorable rejects archives whose manifest would make restore write
+// outside CloneDir or the metadata dir, as archives may come from elsewhere
+func checkRestorable(cfg *types.Config, m *archive.Manifest) error {
+	sess := m.Session
+	if sess == nil {
+		return fmt.Errorf("archive has no session")
+	}
+	if sess.Name == "" || sess.Name == "." || sess.Name == ".." || strings.ContainsAny(sess.Name, `/\`) {
+		return fmt.Errorf("archive has an invalid session name %q", sess.Name)
+	}
+	if !isWithinDir(cfg.CloneDir, sess.ClonePath) {
+		return fmt.Errorf("archived clone path %s is outside %s", sess.ClonePath, cfg.CloneDir)
+	}
+	for _, repo := range m.Repos {
+		if !filepath.IsLocal(repo.Dir) {
+			return fmt.Errorf("archive has an invalid repository dir %q", repo.Dir)
+		}
+	}
+	return nil
+}
+
+// abandonRestore undoes a restore that failed part way: it kills the tmux
+// session if it was started and removes the worktrees and clones created
+// so far, all best-effort
+func abandonRestore(gitMgr *git.Manager, tmuxMgr *tmux.Manager, sess *types.Session, targets []string, tmuxStarted bool, log *logging.Logger) {
+	if tmuxStarted {
+		if err := tmuxMgr.KillSession(sess.Name); err != nil {
+			log.Warnf("⚠️  Failed to kill tmux session: %v\n", err)
+		}
+	}
+	for _, target := range targets {
+		if mirrorPath, ok := gitMgr.WorktreeMirror(target); ok {
+			if err := gitMgr.RemoveWorktree(mirrorPath, target, true); err != nil {
+				log.Warnf("⚠️  Failed to remove worktree %s: %v\n", target, err)
+			}
+		}
+	}
+	if err := os.RemoveAll(sess.ClonePath); err != nil {
+		log.Warnf("⚠️  Failed to remove %s: %v\n", sess.ClonePath, err)
+	}
+}
+
+// restoreRepo recreates one checkout of an archived session at target, as a
+// worktree of the mirror or as a clone referencing it, and returns the
+// mirror path
+func restoreRepo(cfg *types.Config, gitMgr *git.Manager, repo *archive.Repo, target string, worktree bool) (string, error) {
+	if _, err := gitMgr.EnsureMirror(repo.URL, cfg.CacheDir); err != nil {
+		return "", fmt.Errorf("failed to prepare mirror: %w", err)
+	}
+	mirrorPath := gitMgr.GetMirrorPath(repo.URL, cfg.CacheDir)
+
+	// Local commits go where the checkout will look for them
+	repoPath := mirrorPath
+	if !worktree {
+		if err := gitMgr.CloneWithReference(repo.URL, target, mirrorPath); err != nil {
+			return "", err
+		}
+		repoPath = target
+	}
+	if repo.Bundle != "" {
+		if err := gitMgr.FetchBundle(repoPath, repo.Bundle); err != nil {
+			return "", fmt.Errorf("failed to fetch local commits: %w", err)
+		}
+	}
+	// A branch without local commits may never have been pushed
+	if repo.Branch != "" && !gitMgr.BranchExists(repoPath, repo.Branch) {
+		if err := gitMgr.CreateBranch(repoPath, repo.Branch, repo.Head); err != nil {
+			return "", fmt.Errorf("failed to recreate branch %s: %w", repo.Branch, err)
+		}
+	}
+
+	if worktree {
+		if err := gitMgr.AddWorktree(mirrorPath, target, repo.Branch); err != nil {
+			return "", err
+		}
+	} else {
+		rev := repo.Branch
+		if rev == "" {
+			rev = repo.Head
+		}
+		if err := gitMgr.ForceCheckout(target, rev); err != nil {
+			return "", fmt.Errorf("failed to check out %s: %w", rev, err)
+		}
+	}
+
+	if repo.Patch != "" {
+		if err := gitMgr.ApplyPatch(target, repo.Patch); err != nil {
+			return "", fmt.Errorf("failed to reapply uncommitted changes: %w", err)
+		}
+	}
+
+	return mirrorPath, nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mateimicu/tmux-claude-matrix/internal/archive"
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+func TestCheckRestorable(t *testing.T) {
+	cfg := &types.Config{CloneDir: "/home/u/repos"}
+	manifest := func(name, clonePath string, dirs ...string) *archive.Manifest {
+		m := &archive.Manifest{Session: &types.Session{Name: name, ClonePath: clonePath}}
+		for _, dir := range dirs {
+			m.Repos = append(m.Repos, &archive.Repo{Dir: dir})
+		}
+		return m
+	}
+
+	tests := []struct {
+		name     string
+		manifest *archive.Manifest
+		wantErr  bool
+	}{
+		{"repository session", manifest("api-1", "/home/u/repos/api-1", "."), false},
+		{"workspace session", manifest("ws-1", "/home/u/repos/ws-1", "api", "web"), false},
+		{"no session", &archive.Manifest{}, true},
+		{"name with a separator", manifest("../evil", "/home/u/repos/api-1", "."), true},
+		{"dot-dot name", manifest("..", "/home/u/repos/api-1", "."), true},
+		{"clone path outside CloneDir", manifest("api-1", "/etc/api-1", "."), true},
+		{"clone path is CloneDir", manifest("api-1", "/home/u/repos", "."), true},
+		{"absolute repo dir", manifest("ws-1", "/home/u/repos/ws-1", "/etc"), true},
+		{"repo dir escaping the clone", manifest("ws-1", "/home/u/repos/ws-1", "../other"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRestorable(cfg, tt.manifest)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkRestorable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
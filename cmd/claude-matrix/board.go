@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mateimicu/tmux-claude-matrix/internal/board"
+	"github.com/mateimicu/tmux-claude-matrix/internal/status"
+	"github.com/mateimicu/tmux-claude-matrix/internal/tmux"
+)
+
+// boardWatcherEnv holds the PID of the process keeping the board in sync,
+// set on the board session
+const boardWatcherEnv = "CLAUDE_MATRIX_BOARD_WATCHER"
+
+func boardCmd() *cobra.Command {
+	var watch bool
+	var interval time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "board",
+		Short: "Tile every session waiting for input in one tmux window",
+		Long: `Open the attention board: a tmux session named "` + board.SessionName + `" whose
+window has one pane per session waiting for input, each a nested tmux client
+attached to that session, so permission prompts can be answered without
+switching sessions. A background watcher adds and removes panes as sessions
+enter and leave the waiting state, following the aggregate state files, and
+exits when the board session is closed.
+
+With --watch the watcher runs in the foreground instead.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			log := loggerFromContext(cmd.Context())
+			tmuxMgr := tmux.New()
+			statusDir := status.DefaultStatusDir()
+
+			waiting, err := board.Waiting(statusDir)
+			if err != nil {
+				return fmt.Errorf("failed to read session states: %w", err)
+			}
+			if err := board.Sync(tmuxMgr, waiting); err != nil {
+				return err
+			}
+
+			if watch {
+				//nolint:errcheck // Only used to avoid starting a second watcher
+				tmuxMgr.SetSessionEnv(board.SessionName, boardWatcherEnv, strconv.Itoa(os.Getpid()))
+				return board.Watch(cmd.Context(), tmuxMgr, statusDir, interval)
+			}
+
+			if !boardWatcherRunning(tmuxMgr) {
+				binaryPath, err := os.Executable()
+				if err != nil {
+					return fmt.Errorf("failed to get binary path: %w", err)
+				}
+				command := fmt.Sprintf("%s board --watch --interval %s >/dev/null 2>&1", tmux.ShellQuote(binaryPath), interval)
+				if err := tmuxMgr.RunShellBackground(command); err != nil {
+					return fmt.Errorf("failed to start board watcher: %w", err)
+				}
+				log.Debugf("Started board watcher\n")
+			}
+
+			return tmuxMgr.SwitchToSession(board.SessionName)
+		},
+	}
+
+	cmd.Flags().BoolVar(&watch, "watch", false, "Keep the board in sync in the foreground instead of opening it")
+	cmd.Flags().DurationVar(&interval, "interval", board.DefaultInterval, "How often to reread the state files")
+
+	return cmd
+}
+
+// boardWatcherRunning reports whether the PID recorded on the board session
+// is still alive
+func boardWatcherRunning(tmuxMgr *tmux.Manager) bool {
+	value, err := tmuxMgr.GetSessionEnv(board.SessionName, boardWatcherEnv)
+	if err != nil {
+		return false
+	}
+	pid, err := strconv.Atoi(value)
+	if err != nil || pid <= 0 {
+		return false
+	}
+	return syscall.Kill(pid, 0) == nil
+}
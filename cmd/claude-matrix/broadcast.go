@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mateimicu/tmux-claude-matrix/internal/status"
+	"github.com/mateimicu/tmux-claude-matrix/internal/tmux"
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+// sendToSession types text into the pane running Claude in a session
+func sendToSession(tmuxMgr *tmux.Manager, name, text string, enter bool) error {
+	paneID, ok := tmuxMgr.FindClaudePane(name)
+	if !ok {
+		return fmt.Errorf("no pane is running Claude in session %s", name)
+	}
+	return tmuxMgr.SendText(paneID, text, enter)
+}
+
+// aggregateState returns the state in a session's aggregate state file, or
+// an empty state when it has none
+func aggregateState(name string) types.ClaudeState {
+	sf, err := status.ReadState(status.DefaultStatusDir(), name)
+	if err != nil {
+		return ""
+	}
+	return types.ClaudeState(sf.State)
+}
+
+// broadcastSkipReason returns why a session is left out of a broadcast, or
+// an empty string to send to it. With idleOnly only sessions whose aggregate
+// state is idle receive the prompt.
+func broadcastSkipReason(st *types.SessionStatus, state types.ClaudeState, idleOnly bool) string {
+	if !st.TmuxActive {
+		return "tmux session not running"
+	}
+	if idleOnly && state != types.ClaudeStateIdle {
+		if state == "" {
+			return "state unknown"
+		}
+		return "not idle (" + string(state) + ")"
+	}
+	return ""
+}
+
+// handleBroadcastAction sends one prompt to each of the selected sessions
+// and reports the outcome per session
+func handleBroadcastAction(tmuxMgr *tmux.Manager, selected []*types.SessionStatus) error {
+	names := make([]string, len(selected))
+	for i, st := range selected {
+		names[i] = st.Session.Name
+	}
+	fmt.Printf("\n📣 Broadcast to %d session(s): %s\n", len(selected), strings.Join(names, ", "))
+	fmt.Print("Prompt to send (empty to cancel): ")
+
+	reader := bufio.NewReader(os.Stdin)
+	line, _ := reader.ReadString('\n') //nolint:errcheck // EOF leaves an empty prompt
+	text := strings.TrimSpace(line)
+	if text == "" {
+		fmt.Println("Broadcast cancelled.")
+		return nil
+	}
+
+	fmt.Print("Skip sessions that are not idle? (Y/n): ")
+	answer, _ := reader.ReadString('\n') //nolint:errcheck // EOF keeps the default
+	answer = strings.TrimSpace(answer)
+	idleOnly := answer != "n" && answer != "N"
+
+	fmt.Printf("Send %q to %d session(s)? (y/N): ", text, len(selected))
+	answer, _ = reader.ReadString('\n') //nolint:errcheck // EOF cancels
+	if answer = strings.TrimSpace(answer); answer != "y" && answer != "Y" {
+		fmt.Println("Broadcast cancelled.")
+		return nil
+	}
+
+	sent := 0
+	for _, st := range selected {
+		name := st.Session.Name
+		if reason := broadcastSkipReason(st, aggregateState(name), idleOnly); reason != "" {
+			fmt.Printf("⏭️  %s: skipped, %s\n", name, reason)
+			continue
+		}
+		if err := sendToSession(tmuxMgr, name, text, true); err != nil {
+			fmt.Printf("❌ %s: %v\n", name, err)
+			continue
+		}
+		fmt.Printf("✓ %s: sent\n", name)
+		sent++
+	}
+	fmt.Printf("\nSent to %d of %d session(s).\n", sent, len(selected))
+
+	fmt.Print("Press Enter to return to the list...")
+	//nolint:errcheck // intentionally ignoring - just waiting for keypress
+	reader.ReadString('\n')
+	return nil
+}
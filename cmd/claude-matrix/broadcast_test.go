@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+func TestBroadcastSkipReason(t *testing.T) {
+	active := &types.SessionStatus{Session: &types.Session{Name: "a"}, TmuxActive: true}
+	inactive := &types.SessionStatus{Session: &types.Session{Name: "b"}}
+
+	tests := []struct {
+		name     string
+		st       *types.SessionStatus
+		state    types.ClaudeState
+		idleOnly bool
+		want     string
+	}{
+		{"idle session", active, types.ClaudeStateIdle, true, ""},
+		{"running session with idle only", active, types.ClaudeStateRunning, true, "not idle (running)"},
+		{"running session sent anyway", active, types.ClaudeStateRunning, false, ""},
+		{"no state file with idle only", active, "", true, "state unknown"},
+		{"inactive session", inactive, types.ClaudeStateIdle, false, "tmux session not running"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := broadcastSkipReason(tt.st, tt.state, tt.idleOnly); got != tt.want {
+				t.Errorf("broadcastSkipReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
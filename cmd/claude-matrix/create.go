@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,8 +12,10 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/mateimicu/tmux-claude-matrix/internal/config"
 	"github.com/mateimicu/tmux-claude-matrix/internal/fzf"
 	"github.com/mateimicu/tmux-claude-matrix/internal/git"
+	"github.com/mateimicu/tmux-claude-matrix/internal/layout"
 	"github.com/mateimicu/tmux-claude-matrix/internal/logging"
 	"github.com/mateimicu/tmux-claude-matrix/internal/repos"
 	"github.com/mateimicu/tmux-claude-matrix/internal/session"
@@ -19,21 +23,86 @@ import (
 	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
 )
 
+// createOptions holds the flags of the create command. When repo or
+// workspace is set, creation runs without any interactive prompts.
+type createOptions struct {
+	repo       string
+	workspace  string
+	title      string
+	branch     string
+	layout     string
+	prompt     string
+	promptFile string
+	tags       []string
+	noSwitch   bool
+	jsonOutput bool
+}
+
+// interactive reports whether the repository is picked through FZF
+func (o *createOptions) interactive() bool {
+	return o.repo == "" && o.workspace == ""
+}
+
 func createCmd() *cobra.Command {
-	return &cobra.Command{
+	opts := &createOptions{}
+
+	cmd := &cobra.Command{
 		Use:   "create",
 		Short: "Create a new tmux session",
-		Long:  `Create a new tmux session by selecting a repository from configured sources.`,
+		Long: `Create a new tmux session by selecting a repository from configured sources.
+
+With --repo or --workspace the session is created without prompts, which
+makes create usable from scripts. --repo accepts a clone URL or a name
+(org/repo) resolved against the configured repository sources.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runCreate(cmd.Context())
+			if opts.repo != "" && opts.workspace != "" {
+				return fmt.Errorf("--repo and --workspace are mutually exclusive")
+			}
+			if opts.promptFile != "" {
+				if opts.prompt != "" {
+					return fmt.Errorf("--prompt and --prompt-file are mutually exclusive")
+				}
+				data, err := os.ReadFile(opts.promptFile)
+				if err != nil {
+					return fmt.Errorf("failed to read prompt file: %w", err)
+				}
+				opts.prompt = strings.TrimSpace(string(data))
+			}
+			return runCreate(cmd.Context(), opts)
 		},
 	}
+
+	cmd.Flags().StringVar(&opts.repo, "repo", "", "Repository URL or name (org/repo) to create the session for")
+	cmd.Flags().StringVar(&opts.workspace, "workspace", "", "Workspace name to create the session for")
+	cmd.Flags().StringVar(&opts.title, "title", "", "Session title (defaults to the session name)")
+	cmd.Flags().StringVar(&opts.branch, "branch", "", "Branch to work on; checked out if it exists, created otherwise")
+	cmd.Flags().StringVar(&opts.layout, "layout", "", "Window layout from the layouts file")
+	cmd.Flags().StringVar(&opts.prompt, "prompt", "", "Initial task prompt passed to Claude")
+	cmd.Flags().StringVar(&opts.promptFile, "prompt-file", "", "Read the initial task prompt from a file")
+	cmd.Flags().StringSliceVar(&opts.tags, "tag", nil, "Tag the session (repeatable or comma-separated)")
+	cmd.Flags().BoolVar(&opts.noSwitch, "no-switch", false, "Do not switch to the new session")
+	cmd.Flags().BoolVar(&opts.jsonOutput, "json", false, "Print the created session as JSON")
+
+	return cmd
 }
 
-func runCreate(ctx context.Context) error {
+func runCreate(ctx context.Context, opts *createOptions) error {
 	cfg := configFromContext(ctx)
 	log := loggerFromContext(ctx)
 
+	gitMgr := git.New()
+	gitMgr.SetSessionBranchPattern(cfg.BranchPattern)
+
+	// Keep stdout clean for the JSON result
+	if opts.jsonOutput {
+		debugW := io.Writer(io.Discard)
+		if cfg.Debug {
+			debugW = os.Stderr
+		}
+		log = &logging.Logger{DebugW: debugW, WarnW: log.WarnW}
+		gitMgr.SetOutput(os.Stderr)
+	}
+
 	// Build sources list
 	sources, err := buildSources(ctx, cfg, log)
 	if err != nil {
@@ -52,102 +121,199 @@ func runCreate(ctx context.Context) error {
 		return fmt.Errorf("failed to discover repositories: %w", err)
 	}
 
-	if len(repoList) == 0 {
-		return fmt.Errorf("no repositories found")
-	}
-
 	log.Debugf("✓ Found %d repositories\n", len(repoList))
 
-	// Get binary path for FZF reload
-	binaryPath, err := os.Executable()
-	if err != nil {
-		return fmt.Errorf("failed to get binary path: %w", err)
-	}
+	var selected *types.Repository
+	if opts.interactive() {
+		if len(repoList) == 0 {
+			return fmt.Errorf("no repositories found")
+		}
 
-	// Let user select
-	selected, err := fzf.SelectRepository(repoList, binaryPath)
-	if err != nil {
-		return fmt.Errorf("repository selection cancelled: %w", err)
+		// Get binary path for FZF reload
+		binaryPath, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("failed to get binary path: %w", err)
+		}
+
+		// Let user select
+		selected, err = fzf.SelectRepository(repoList, binaryPath)
+		if err != nil {
+			return fmt.Errorf("repository selection cancelled: %w", err)
+		}
+	} else {
+		selected, err = resolveCreateTarget(repoList, opts.repo, opts.workspace)
+		if err != nil {
+			return err
+		}
 	}
 
 	sessionMgr := session.NewManager(cfg.SessionsDir)
-	gitMgr := git.New()
 	tmuxMgr := tmux.New()
 
 	if selected.IsWorkspace {
-		return createWorkspaceSession(cfg, selected, sessionMgr, gitMgr, tmuxMgr, log)
+		return createWorkspaceSession(cfg, opts, selected, sessionMgr, gitMgr, tmuxMgr, log)
+	}
+
+	return createRepoSession(cfg, opts, selected, sessionMgr, gitMgr, tmuxMgr, log)
+}
+
+// resolveCreateTarget finds the repository or workspace named on the command
+// line. Repositories match by URL, then by name; a URL that no source lists
+// is used as-is.
+func resolveCreateTarget(repoList []*types.Repository, repoArg, workspaceArg string) (*types.Repository, error) {
+	if workspaceArg != "" {
+		for _, repo := range repoList {
+			if repo.IsWorkspace && repo.Name == workspaceArg {
+				return repo, nil
+			}
+		}
+		return nil, fmt.Errorf("workspace %q not found", workspaceArg)
+	}
+
+	for _, repo := range repoList {
+		if !repo.IsWorkspace && repo.URL == repoArg {
+			return repo, nil
+		}
 	}
 
-	return createRepoSession(cfg, selected, sessionMgr, gitMgr, tmuxMgr, log)
+	// Match by name, also accepting a URL in a different format (SSH vs HTTPS)
+	wantName := repoArg
+	if looksLikeRepoURL(repoArg) {
+		wantName = git.ExtractRepoName(repoArg)
+	}
+	var matches []*types.Repository
+	for _, repo := range repoList {
+		if repo.IsWorkspace {
+			continue
+		}
+		if repo.Name == wantName || git.ExtractRepoName(repo.URL) == wantName {
+			matches = append(matches, repo)
+		}
+	}
+
+	switch {
+	case len(matches) == 1:
+		return matches[0], nil
+	case len(matches) > 1:
+		urls := make([]string, 0, len(matches))
+		for _, m := range matches {
+			urls = append(urls, m.URL)
+		}
+		return nil, fmt.Errorf("repository %q is ambiguous, use one of: %s", repoArg, strings.Join(urls, ", "))
+	case looksLikeRepoURL(repoArg):
+		return &types.Repository{
+			Source: "url",
+			URL:    repoArg,
+			Name:   git.ExtractRepoName(repoArg),
+		}, nil
+	default:
+		return nil, fmt.Errorf("repository %q not found", repoArg)
+	}
 }
 
-func createRepoSession(cfg *types.Config, selected *types.Repository, sessionMgr *session.Manager, gitMgr *git.Manager, tmuxMgr *tmux.Manager, log *logging.Logger) error {
+// looksLikeRepoURL reports whether s is a clone URL or local path rather than a name
+func looksLikeRepoURL(s string) bool {
+	return strings.Contains(s, "://") || strings.Contains(s, "@") || filepath.IsAbs(s)
+}
+
+func createRepoSession(cfg *types.Config, opts *createOptions, selected *types.Repository, sessionMgr *session.Manager, gitMgr *git.Manager, tmuxMgr *tmux.Manager, log *logging.Logger) error {
 	repoName := git.ExtractRepoName(selected.URL)
 	sessionName, err := sessionMgr.GenerateUniqueName(repoName)
 	if err != nil {
 		return fmt.Errorf("failed to generate session name: %w", err)
 	}
+	defer sessionMgr.Release(sessionName)
+
+	layoutName, err := resolveLayoutName(cfg, opts.layout, repoName)
+	if err != nil {
+		return err
+	}
 
 	clonePath := filepath.Join(cfg.CloneDir, sessionName)
 
+	var mirrorPath, branch string
 	if _, err := os.Stat(clonePath); err == nil {
 		log.Debugf("📦 Repository already exists at %s\n", clonePath)
+		branch, _ = gitMgr.CurrentBranch(clonePath) //nolint:errcheck // Detached HEAD leaves the branch empty
 	} else {
-		log.Debugf("📦 Cloning %s (using cache for faster cloning)...\n", selected.URL)
-		if err := gitMgr.CloneWithCache(selected.URL, clonePath, cfg.CacheDir); err != nil {
-			return fmt.Errorf("failed to clone repository: %w", err)
+		log.Debugf("📦 Updating mirror cache for %s...\n", selected.URL)
+		if _, err := gitMgr.EnsureMirror(selected.URL, cfg.CacheDir); err != nil {
+			return fmt.Errorf("failed to update mirror cache: %w", err)
 		}
-		log.Debugf("✓ Clone complete\n")
-	}
+		cachePath := gitMgr.GetMirrorPath(selected.URL, cfg.CacheDir)
 
-	var claudeCmd string
-	if cfg.ClaudeBin != "" {
-		claudeCmd = cfg.ClaudeBin + " " + strings.Join(cfg.ClaudeArgs, " ")
+		var create bool
+		branch, create, err = resolveSessionBranch(cfg, opts, gitMgr, cachePath, sessionName)
+		if err != nil {
+			return err
+		}
+
+		if cfg.UseWorktrees {
+			log.Debugf("🌿 Adding worktree on branch %s...\n", branch)
+			if err := gitMgr.AddWorktree(cachePath, clonePath, branch); err != nil {
+				return fmt.Errorf("failed to add worktree: %w", err)
+			}
+			mirrorPath = cachePath
+			log.Debugf("✓ Worktree ready\n")
+		} else {
+			log.Debugf("📦 Cloning %s (using cache for faster cloning)...\n", selected.URL)
+			if err := gitMgr.CloneWithReference(selected.URL, clonePath, cachePath); err != nil {
+				return fmt.Errorf("failed to clone repository: %w", err)
+			}
+			if branch != "" {
+				if err := gitMgr.CheckoutBranch(clonePath, branch, create); err != nil {
+					return fmt.Errorf("failed to check out branch %s: %w", branch, err)
+				}
+			}
+			log.Debugf("✓ Clone complete\n")
+		}
 	}
 
+	claudeCmd := buildClaudeCommand(cfg, opts.prompt)
+
 	log.Debugf("🚀 Creating tmux session '%s'...\n", sessionName)
-	if err := tmuxMgr.CreateSession(sessionName, clonePath, claudeCmd); err != nil {
+	if err := startTmuxSession(cfg, tmuxMgr, sessionName, clonePath, claudeCmd, layoutName); err != nil {
 		return fmt.Errorf("failed to create tmux session: %w", err)
 	}
 
 	sess := &types.Session{
-		Name:      sessionName,
-		RepoURL:   selected.URL,
-		Title:     sessionName,
-		ClonePath: clonePath,
-		CreatedAt: time.Now(),
-	}
-	if err := sessionMgr.Save(sess); err != nil {
-		log.Warnf("⚠️  Failed to save session metadata: %v\n", err)
+		Name:       sessionName,
+		RepoURL:    selected.URL,
+		Title:      sessionName,
+		ClonePath:  clonePath,
+		Branch:     branch,
+		Prompt:     opts.prompt,
+		Layout:     layoutName,
+		CreatedAt:  time.Now(),
+		MirrorPath: mirrorPath,
 	}
-
-	// Set tmux session env var for status bar display
-	if err := tmuxMgr.SetSessionEnv(sessionName, "@claude-matrix-title", sessionName); err != nil {
-		log.Warnf("⚠️  Failed to set session title env: %v\n", err)
-	}
-
-	// User-facing success confirmation — always visible
-	fmt.Printf("✓ Session created: %s\n", sessionName)
-
-	if err := tmuxMgr.SwitchToSession(sessionName); err != nil {
-		log.Warnf("⚠️  Failed to switch to session: %v\n", err)
-		log.Warnf("You can attach manually with: tmux attach -t %s\n", sessionName)
-	}
-
-	return nil
+	return finishCreate(cfg, opts, sess, sessionMgr, tmuxMgr, log)
 }
 
-func createWorkspaceSession(cfg *types.Config, selected *types.Repository, sessionMgr *session.Manager, gitMgr *git.Manager, tmuxMgr *tmux.Manager, log *logging.Logger) error {
+func createWorkspaceSession(cfg *types.Config, opts *createOptions, selected *types.Repository, sessionMgr *session.Manager, gitMgr *git.Manager, tmuxMgr *tmux.Manager, log *logging.Logger) error {
 	sessionName, err := sessionMgr.GenerateUniqueName(selected.Name)
 	if err != nil {
 		return fmt.Errorf("failed to generate session name: %w", err)
 	}
+	defer sessionMgr.Release(sessionName)
+
+	layoutName, err := resolveLayoutName(cfg, opts.layout, selected.Name)
+	if err != nil {
+		return err
+	}
 
 	workspacePath := filepath.Join(cfg.CloneDir, sessionName)
 	if err := os.MkdirAll(workspacePath, 0755); err != nil {
 		return fmt.Errorf("failed to create workspace directory: %w", err)
 	}
 
+	// Every sub-repo gets the same branch; existing branches differ per
+	// repo, so there is no branch selection step for workspaces.
+	branch := opts.branch
+	if branch == "" {
+		branch = sessionBranchName(cfg.BranchPattern, sessionName)
+	}
+
 	log.Debugf("📦 Setting up workspace '%s' with %d repos...\n", selected.Name, len(selected.WorkspaceRepos))
 
 	for _, repoURL := range selected.WorkspaceRepos {
@@ -163,17 +329,20 @@ func createWorkspaceSession(cfg *types.Config, selected *types.Repository, sessi
 			if err := gitMgr.Clone(repoURL, clonePath); err != nil {
 				return fmt.Errorf("failed to clone %s: %w", repoURL, err)
 			}
+			if branch != "" {
+				create := !gitMgr.BranchExists(clonePath, branch)
+				if err := gitMgr.CheckoutBranch(clonePath, branch, create); err != nil {
+					return fmt.Errorf("failed to check out branch %s in %s: %w", branch, repoName, err)
+				}
+			}
 			log.Debugf("  ✓ %s cloned\n", repoName)
 		}
 	}
 
-	var claudeCmd string
-	if cfg.ClaudeBin != "" {
-		claudeCmd = cfg.ClaudeBin + " " + strings.Join(cfg.ClaudeArgs, " ")
-	}
+	claudeCmd := buildClaudeCommand(cfg, opts.prompt)
 
 	log.Debugf("🚀 Creating tmux session '%s'...\n", sessionName)
-	if err := tmuxMgr.CreateSession(sessionName, workspacePath, claudeCmd); err != nil {
+	if err := startTmuxSession(cfg, tmuxMgr, sessionName, workspacePath, claudeCmd, layoutName); err != nil {
 		return fmt.Errorf("failed to create tmux session: %w", err)
 	}
 
@@ -183,24 +352,151 @@ func createWorkspaceSession(cfg *types.Config, selected *types.Repository, sessi
 		Title:     sessionName,
 		RepoURLs:  selected.WorkspaceRepos,
 		ClonePath: workspacePath,
+		Branch:    branch,
+		Prompt:    opts.prompt,
+		Layout:    layoutName,
 		CreatedAt: time.Now(),
 	}
+	return finishCreate(cfg, opts, sess, sessionMgr, tmuxMgr, log)
+}
+
+// finishCreate saves the metadata of a freshly created tmux session, reports
+// it and switches to it.
+func finishCreate(cfg *types.Config, opts *createOptions, sess *types.Session, sessionMgr *session.Manager, tmuxMgr *tmux.Manager, log *logging.Logger) error {
+	if opts.title != "" {
+		sess.Title = opts.title
+	}
+	sess.Tags = session.ParseTags(opts.tags...)
+
+	if opts.prompt != "" && cfg.ClaudeBin == "" {
+		log.Warnf("⚠️  Claude binary not found, initial prompt was not sent\n")
+	}
+
 	if err := sessionMgr.Save(sess); err != nil {
 		log.Warnf("⚠️  Failed to save session metadata: %v\n", err)
 	}
 
 	// Set tmux session env var for status bar display
-	if err := tmuxMgr.SetSessionEnv(sessionName, "@claude-matrix-title", sessionName); err != nil {
+	if err := tmuxMgr.SetSessionEnv(sess.Name, "@claude-matrix-title", sess.Title); err != nil {
 		log.Warnf("⚠️  Failed to set session title env: %v\n", err)
 	}
 
-	// User-facing success confirmation — always visible
-	fmt.Printf("✓ Workspace session created: %s\n", sessionName)
+	if opts.jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(sess); err != nil {
+			return fmt.Errorf("failed to encode session: %w", err)
+		}
+	} else {
+		label := "Session"
+		if len(sess.RepoURLs) > 0 {
+			label = "Workspace session"
+		}
+		// User-facing success confirmation — always visible
+		fmt.Printf("✓ %s created: %s\n", label, sess.Name)
+	}
+
+	if opts.noSwitch {
+		return nil
+	}
 
-	if err := tmuxMgr.SwitchToSession(sessionName); err != nil {
+	if err := tmuxMgr.SwitchToSession(sess.Name); err != nil {
 		log.Warnf("⚠️  Failed to switch to session: %v\n", err)
-		log.Warnf("You can attach manually with: tmux attach -t %s\n", sessionName)
+		log.Warnf("You can attach manually with: tmux attach -t %s\n", sess.Name)
 	}
 
 	return nil
 }
+
+// resolveLayoutName picks the window layout for a new session targeting
+// target (org/repo or workspace name). Returns an empty name for the default
+// single Claude window.
+func resolveLayoutName(cfg *types.Config, requested, target string) (string, error) {
+	layouts, err := layout.Load(cfg.LayoutsFile)
+	if err != nil {
+		return "", err
+	}
+	return layouts.Resolve(requested, target)
+}
+
+// startTmuxSession creates the tmux session rooted at path, either as the
+// named layout or as a single window running claudeCmd.
+func startTmuxSession(cfg *types.Config, tmuxMgr *tmux.Manager, sessionName, path, claudeCmd, layoutName string) error {
+	if layoutName == "" {
+		return tmuxMgr.CreateSession(sessionName, path, claudeCmd)
+	}
+
+	layouts, err := layout.Load(cfg.LayoutsFile)
+	if err != nil {
+		return err
+	}
+	l, err := layouts.Get(layoutName)
+	if err != nil {
+		return err
+	}
+
+	existed := tmuxMgr.SessionExists(sessionName)
+	if err := layout.Apply(tmuxMgr, sessionName, path, claudeCmd, l); err != nil {
+		// Don't leave a session with only some of the windows behind, but
+		// never kill one that was there before
+		if !existed && tmuxMgr.SessionExists(sessionName) {
+			tmuxMgr.KillSession(sessionName) //nolint:errcheck // Best-effort cleanup, already failing
+		}
+		return err
+	}
+	return nil
+}
+
+// buildClaudeCommand returns the shell command that starts Claude in a
+// session, with prompt passed as the initial task when non-empty.
+// Returns an empty string when no Claude binary is configured.
+func buildClaudeCommand(cfg *types.Config, prompt string) string {
+	if cfg.ClaudeBin == "" {
+		return ""
+	}
+
+	claudeCmd := cfg.ClaudeBin + " " + strings.Join(cfg.ClaudeArgs, " ")
+	if prompt != "" {
+		// "--" keeps a prompt starting with a dash from being read as a flag
+		claudeCmd += " -- " + tmux.ShellQuote(prompt)
+	}
+	return claudeCmd
+}
+
+// sessionBranchName expands a branch pattern for the given session name.
+// Returns an empty string when branch creation is disabled.
+func sessionBranchName(pattern, sessionName string) string {
+	return strings.ReplaceAll(pattern, "{session}", sessionName)
+}
+
+// resolveSessionBranch picks the branch a new repository session works on:
+// the --branch flag if given, otherwise a fresh branch from the configured
+// pattern, or in interactive mode the user's choice among the fresh branch
+// and the existing branches of the mirror at mirrorPath.
+// Returns the branch and whether it needs to be created. An empty branch
+// means the clone stays on the default branch.
+func resolveSessionBranch(cfg *types.Config, opts *createOptions, gitMgr *git.Manager, mirrorPath, sessionName string) (string, bool, error) {
+	if opts.branch != "" {
+		return opts.branch, !gitMgr.BranchExists(mirrorPath, opts.branch), nil
+	}
+
+	newBranch := sessionBranchName(cfg.BranchPattern, sessionName)
+	if newBranch == "" && cfg.UseWorktrees {
+		// Worktrees cannot share a checked-out branch, so one is always needed
+		newBranch = sessionBranchName(config.DefaultBranchPattern, sessionName)
+	}
+	if newBranch == "" || !opts.interactive() {
+		return newBranch, newBranch != "", nil
+	}
+
+	branches, err := gitMgr.ListBranches(mirrorPath)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	branch, create, err := fzf.SelectBranch(newBranch, branches)
+	if err != nil {
+		return "", false, fmt.Errorf("branch selection cancelled: %w", err)
+	}
+	return branch, create, nil
+}
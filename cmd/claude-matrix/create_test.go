@@ -0,0 +1,151 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+func TestSessionBranchName(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		session  string
+		expected string
+	}{
+		{
+			name:     "default pattern",
+			pattern:  "claude/{session}",
+			session:  "org-repo-1",
+			expected: "claude/org-repo-1",
+		},
+		{
+			name:     "custom prefix and suffix",
+			pattern:  "agents/{session}-wip",
+			session:  "org-repo",
+			expected: "agents/org-repo-wip",
+		},
+		{
+			name:     "empty pattern disables branch creation",
+			pattern:  "",
+			session:  "org-repo",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sessionBranchName(tt.pattern, tt.session)
+			if got != tt.expected {
+				t.Errorf("sessionBranchName(%q, %q) = %q, want %q", tt.pattern, tt.session, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResolveCreateTarget(t *testing.T) {
+	repoList := []*types.Repository{
+		{Source: "github", URL: "https://github.com/org/api", Name: "org/api"},
+		{Source: "local", URL: "git@github.com:org/web.git", Name: "org/web"},
+		{Source: "github", URL: "https://github.com/other/web", Name: "other/web"},
+		{Source: "workspace", Name: "fullstack", IsWorkspace: true, WorkspaceRepos: []string{"https://github.com/org/api"}},
+	}
+
+	tests := []struct {
+		name      string
+		repo      string
+		workspace string
+		wantURL   string
+		wantName  string
+		wantErr   bool
+	}{
+		{name: "exact URL", repo: "https://github.com/org/api", wantURL: "https://github.com/org/api"},
+		{name: "name", repo: "org/web", wantURL: "git@github.com:org/web.git"},
+		{name: "URL in another format matches by name", repo: "git@github.com:org/api.git", wantURL: "https://github.com/org/api"},
+		{name: "unknown URL used as-is", repo: "https://gitlab.com/team/tool.git", wantURL: "https://gitlab.com/team/tool.git"},
+		{name: "unknown name", repo: "org/missing", wantErr: true},
+		{name: "workspace", workspace: "fullstack", wantName: "fullstack"},
+		{name: "unknown workspace", workspace: "missing", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveCreateTarget(repoList, tt.repo, tt.workspace)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("resolveCreateTarget(%q, %q) expected error, got %+v", tt.repo, tt.workspace, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveCreateTarget(%q, %q) error = %v", tt.repo, tt.workspace, err)
+			}
+			if tt.wantURL != "" && got.URL != tt.wantURL {
+				t.Errorf("URL = %q, want %q", got.URL, tt.wantURL)
+			}
+			if tt.wantName != "" && got.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", got.Name, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestResolveCreateTargetAmbiguous(t *testing.T) {
+	repoList := []*types.Repository{
+		{Source: "github", URL: "https://github.com/org/api", Name: "org/api"},
+		{Source: "local", URL: "/src/org/api", Name: "org/api"},
+	}
+
+	_, err := resolveCreateTarget(repoList, "org/api", "")
+	if err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("resolveCreateTarget() error = %v, want ambiguity error", err)
+	}
+}
+
+func TestBuildClaudeCommand(t *testing.T) {
+	cfg := &types.Config{
+		ClaudeBin:  "/usr/local/bin/claude",
+		ClaudeArgs: []string{"--dangerously-skip-permissions"},
+	}
+
+	tests := []struct {
+		name     string
+		cfg      *types.Config
+		prompt   string
+		expected string
+	}{
+		{
+			name:     "no prompt",
+			cfg:      cfg,
+			expected: "/usr/local/bin/claude --dangerously-skip-permissions",
+		},
+		{
+			name:     "prompt is quoted",
+			cfg:      cfg,
+			prompt:   "fix flaky test X",
+			expected: "/usr/local/bin/claude --dangerously-skip-permissions -- 'fix flaky test X'",
+		},
+		{
+			name:     "prompt starting with a dash is not a flag",
+			cfg:      cfg,
+			prompt:   "-v prints nothing",
+			expected: "/usr/local/bin/claude --dangerously-skip-permissions -- '-v prints nothing'",
+		},
+		{
+			name:     "no claude binary",
+			cfg:      &types.Config{},
+			prompt:   "fix flaky test X",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildClaudeCommand(tt.cfg, tt.prompt)
+			if got != tt.expected {
+				t.Errorf("buildClaudeCommand() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mateimicu/tmux-claude-matrix/internal/daemon"
+	"github.com/mateimicu/tmux-claude-matrix/internal/logging"
+	"github.com/mateimicu/tmux-claude-matrix/internal/session"
+	"github.com/mateimicu/tmux-claude-matrix/internal/status"
+	"github.com/mateimicu/tmux-claude-matrix/internal/tmux"
+	"github.com/mateimicu/tmux-claude-matrix/pkg/client"
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+func daemonCmd() *cobra.Command {
+	var interval, probeInterval time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Keep the state of all sessions in memory for fast queries",
+		Long: `Run in the foreground, keeping an index of the status of every session
+that list, next and status-line query over DAEMON_SOCKET instead of probing
+tmux and the Claude processes themselves. The daemon rescans the status dir
+and tmux every --interval and probes the Claude processes of each session
+every --probe-interval, clearing the state of sessions whose Claude is gone
+and expiring agents whose hooks stopped firing.
+
+The daemon is optional: without it every command computes the statuses
+itself.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := configFromContext(cmd.Context())
+			log := loggerFromContext(cmd.Context())
+
+			d := daemon.New(session.NewManager(cfg.SessionsDir), tmux.New(), log, daemon.Options{
+				StatusDir:     status.DefaultStatusDir(),
+				Interval:      interval,
+				ProbeInterval: probeInterval,
+			})
+			log.Debugf("Listening on %s\n", cfg.DaemonSocket)
+			return d.Run(cmd.Context(), cfg.DaemonSocket)
+		},
+	}
+
+	cmd.Flags().DurationVar(&interval, "interval", daemon.DefaultInterval, "How often to rescan the status dir and tmux")
+	cmd.Flags().DurationVar(&probeInterval, "probe-interval", daemon.DefaultProbeInterval, "How often to check the Claude processes of each session")
+
+	return cmd
+}
+
+// loadSessionStatuses returns the status of every managed session. The
+// runtime state comes from the daemon when one is running; sessions it
+// doesn't know yet, or all of them without a daemon, are probed directly.
+func loadSessionStatuses(ctx context.Context, cfg *types.Config, log *logging.Logger, sessionMgr *session.Manager, tmuxMgr *tmux.Manager) ([]*types.SessionStatus, error) {
+	// Metadata is always read locally so renames and deletes show up at once
+	sessions, err := sessionMgr.List()
+	var corrupt *session.CorruptError
+	if errors.As(err, &corrupt) {
+		log.Warnf("⚠️  Skipping unreadable session metadata: %v\n", corrupt)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	if len(sessions) == 0 {
+		return nil, nil
+	}
+
+	indexed := make(map[string]*types.SessionStatus)
+	if statuses, err := client.New(cfg.DaemonSocket).List(ctx); err == nil {
+		for _, st := range statuses {
+			indexed[st.Session.Name] = st
+		}
+	} else {
+		log.Debugf("Daemon unavailable, probing sessions directly: %v\n", err)
+	}
+
+	var activeMap map[string]bool
+	statusList := make([]*types.SessionStatus, 0, len(sessions))
+	for _, sess := range sessions {
+		if st, ok := indexed[sess.Name]; ok {
+			statusList = append(statusList, &types.SessionStatus{
+				Session:       sess,
+				TmuxActive:    st.TmuxActive,
+				ClaudeRunning: st.ClaudeRunning,
+				ClaudeState:   st.ClaudeState,
+				LastActivity:  st.LastActivity,
+			})
+			continue
+		}
+
+		if activeMap == nil {
+			activeSessions, err := tmuxMgr.ListSessions()
+			if err != nil {
+				return nil, fmt.Errorf("failed to list tmux sessions: %w", err)
+			}
+			activeMap = make(map[string]bool, len(activeSessions))
+			for _, name := range activeSessions {
+				activeMap[name] = true
+			}
+		}
+		statusList = append(statusList, buildSessionStatus(tmuxMgr, sess, activeMap[sess.Name]))
+	}
+	return statusList, nil
+}
+
+// statesFromDaemon returns the aggregate states of the live sessions known
+// to the daemon, managed or not, in the shape of the status dir, and which
+// sessions are live. Only hook states are included, so the result matches
+// what the status dir and tmux give without a daemon. ok is false when no
+// daemon is running.
+func statesFromDaemon(ctx context.Context, cfg *types.Config) (states map[string]*status.StateFile, live map[string]bool, ok bool) {
+	sessions, unmanaged, err := client.New(cfg.DaemonSocket).ListAll(ctx)
+	if err != nil {
+		return nil, nil, false
+	}
+	states, live = statesFromStatuses(append(sessions, unmanaged...))
+	return states, live, true
+}
+
+// statesFromStatuses converts session statuses into aggregate states and
+// tmux liveness keyed by session name. Sessions without an aggregate state
+// have no entry, as in the status dir.
+func statesFromStatuses(statuses []*types.SessionStatus) (map[string]*status.StateFile, map[string]bool) {
+	states := make(map[string]*status.StateFile)
+	live := make(map[string]bool)
+	for _, st := range statuses {
+		if !st.TmuxActive {
+			continue
+		}
+		live[st.Session.Name] = true
+		if st.AggregateState == "" {
+			continue
+		}
+		states[st.Session.Name] = &status.StateFile{
+			State: string(st.AggregateState),
+			Since: st.StateSince,
+		}
+	}
+	return states, live
+}
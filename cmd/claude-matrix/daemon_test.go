@@ -0,0 +1,31 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+func TestStatesFromStatuses(t *testing.T) {
+	waitingSince := time.Now().Add(-time.Minute)
+	statuses := []*types.SessionStatus{
+		{Session: &types.Session{Name: "waiting"}, TmuxActive: true, ClaudeState: types.ClaudeStateWaitingForInput,
+			AggregateState: types.ClaudeStateWaitingForInput, StateSince: waitingSince},
+		// Process-based states don't come from a state file
+		{Session: &types.Session{Name: "plain"}, TmuxActive: true, ClaudeState: types.ClaudeStateUnknown},
+		{Session: &types.Session{Name: "closed"}, ClaudeState: types.ClaudeStateStopped},
+	}
+
+	states, live := statesFromStatuses(statuses)
+
+	if len(states) != 1 || states["waiting"] == nil {
+		t.Fatalf("expected only the waiting session to have a state, got %v", states)
+	}
+	if sf := states["waiting"]; sf.State != string(types.ClaudeStateWaitingForInput) || !sf.Since.Equal(waitingSince) {
+		t.Errorf("waiting state = %+v", sf)
+	}
+	if !live["waiting"] || !live["plain"] || live["closed"] {
+		t.Errorf("live = %v, want waiting and plain", live)
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mateimicu/tmux-claude-matrix/internal/dashboard"
+	"github.com/mateimicu/tmux-claude-matrix/internal/fzf"
+	"github.com/mateimicu/tmux-claude-matrix/internal/git"
+	"github.com/mateimicu/tmux-claude-matrix/internal/session"
+	"github.com/mateimicu/tmux-claude-matrix/internal/status"
+	"github.com/mateimicu/tmux-claude-matrix/internal/tmux"
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+// gitStatusTTL is how long the git summary of a session is reused; it runs
+// several git commands per session
+const gitStatusTTL = 30 * time.Second
+
+func dashboardCmd() *cobra.Command {
+	var refresh time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "dashboard",
+		Short: "Watch all sessions on a live full-screen dashboard",
+		Long: `Show every session with its Claude state, how long it has been in that
+state, branch, uncommitted changes and the last line of its Claude pane,
+sessions needing attention first. The dashboard updates as soon as a state
+file changes and reloads everything every --refresh. The list view's keys
+switch to, delete, rename, tag and annotate the selected session.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDashboard(cmd.Context(), refresh)
+		},
+	}
+
+	cmd.Flags().DurationVar(&refresh, "refresh", dashboard.DefaultRefreshInterval, "How often to reload pane output and git status")
+
+	return cmd
+}
+
+func runDashboard(ctx context.Context, refresh time.Duration) error {
+	cfg := configFromContext(ctx)
+	log := loggerFromContext(ctx)
+
+	sessionMgr := session.NewManager(cfg.SessionsDir)
+	gitMgr := git.New()
+	tmuxMgr := tmux.New()
+	gitCache := make(map[string]cachedGitStatus)
+	statusDir := status.DefaultStatusDir()
+
+	load := func() ([]*dashboard.Row, error) {
+		statuses, err := loadSessionStatuses(ctx, cfg, log, sessionMgr, tmuxMgr)
+		if err != nil {
+			return nil, err
+		}
+
+		now := time.Now()
+		var rows []*dashboard.Row
+		for _, st := range fzf.SortSessions(statuses, fzf.SortByAttention) {
+			row := &dashboard.Row{Status: st}
+
+			path := st.Session.ClonePath
+			cached, ok := gitCache[path]
+			if !ok || now.Sub(cached.at) >= gitStatusTTL {
+				cached = cachedGitStatus{at: now}
+				if repoStatus, err := gitMgr.CheckRepoStatus(path); err == nil {
+					cached.summary = gitSummary(repoStatus)
+				}
+				gitCache[path] = cached
+			}
+			row.Git = cached.summary
+
+			// Only hooks record when a state was entered
+			if sf, err := status.ReadState(statusDir, st.Session.Name); err == nil && types.ClaudeState(sf.State) == st.ClaudeState {
+				row.Since = sf.Since
+			}
+
+			if st.TmuxActive {
+				// Best-effort, like the list preview
+				pane, _ := tmuxMgr.CaptureClaudePane(st.Session.Name, 10)
+				row.LastLine = lastNonBlankLine(pane)
+			}
+			rows = append(rows, row)
+		}
+		return rows, nil
+	}
+
+	handle := func(in dashboard.Input, row *dashboard.Row) (bool, error) {
+		switch in {
+		case dashboard.InputSwitch:
+			return true, handleSwitchAction(cfg, tmuxMgr, row.Status, log)
+		case dashboard.InputDelete:
+			err := handleDeleteAction(cfg, sessionMgr, gitMgr, tmuxMgr, row.Status, log)
+			return false, pauseAfter(err)
+		case dashboard.InputRename:
+			return false, handleRenameAction(sessionMgr, tmuxMgr, row.Status)
+		case dashboard.InputTag:
+			return false, handleTagAction(sessionMgr, row.Status)
+		case dashboard.InputNotes:
+			return false, editNotes(sessionMgr, row.Status.Session.Name)
+		case dashboard.InputTools:
+			return false, pauseAfter(handleToolsAction(ctx, cfg))
+		}
+		return false, nil
+	}
+
+	return dashboard.Run(ctx, dashboard.Options{
+		StatusDir:       statusDir,
+		Load:            load,
+		Handle:          handle,
+		RefreshInterval: refresh,
+	})
+}
+
+// cachedGitStatus is the git summary of a session directory
+type cachedGitStatus struct {
+	at      time.Time
+	summary string
+}
+
+// gitSummary returns a compact form of a repo status for the GIT column,
+// e.g. "3M ↑2" for three uncommitted files and two unpushed commits
+func gitSummary(s *git.RepoStatus) string {
+	if s.Clean() {
+		return "clean"
+	}
+	var parts []string
+	if s.Uncommitted > 0 {
+		parts = append(parts, fmt.Sprintf("%dM", s.Uncommitted))
+	}
+	if s.Unpushed > 0 {
+		parts = append(parts, fmt.Sprintf("↑%d", s.Unpushed))
+	}
+	if s.Stashes > 0 {
+		parts = append(parts, fmt.Sprintf("%dS", s.Stashes))
+	}
+	return strings.Join(parts, " ")
+}
+
+// lastNonBlankLine returns the last line of content with text on it
+func lastNonBlankLine(content string) string {
+	lines := strings.Split(content, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if line := strings.TrimSpace(lines[i]); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// pauseAfter keeps the output of an action that prints a report on screen
+// until the user presses Enter, then passes err through
+func pauseAfter(err error) error {
+	fmt.Print("\nPress Enter to return to the dashboard...")
+	//nolint:errcheck // intentionally ignoring - just waiting for keypress
+	bufio.NewReader(os.Stdin).ReadBytes('\n')
+	return err
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mateimicu/tmux-claude-matrix/internal/git"
+	"github.com/mateimicu/tmux-claude-matrix/internal/logging"
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+// removeSessionCheckout removes the directory a session was created in, when
+// the user asks for it. Clones outside CloneDir are always left in place. When
+// removing would lose local work the user has to type "force" to go ahead.
+func removeSessionCheckout(cfg *types.Config, gitMgr *git.Manager, sess *types.Session, log *logging.Logger) error {
+	if sess.ClonePath == "" {
+		return nil
+	}
+	if _, err := os.Stat(sess.ClonePath); os.IsNotExist(err) {
+		if sess.MirrorPath != "" {
+			// Prune the stale worktree entry left in the mirror
+			return gitMgr.RemoveWorktree(sess.MirrorPath, sess.ClonePath, false)
+		}
+		return nil
+	}
+
+	worktree := sess.MirrorPath != ""
+	if worktree {
+		fmt.Printf("🌿 Also remove worktree '%s'? (y/N): ", sess.ClonePath)
+	} else {
+		if !isWithinDir(cfg.CloneDir, sess.ClonePath) {
+			log.Debugf("Clone path '%s' is outside %s, leaving it in place\n", sess.ClonePath, cfg.CloneDir)
+			return nil
+		}
+		fmt.Printf("📁 Also remove clone directory '%s'? (y/N): ", sess.ClonePath)
+	}
+	if answer := readAnswer(); answer != "y" && answer != "Y" {
+		return nil
+	}
+
+	atRisk := localWork(gitMgr, sess.ClonePath)
+	force := false
+	if len(atRisk) > 0 {
+		fmt.Printf("⚠️  Removing '%s' would lose local work:\n", sess.ClonePath)
+		for _, line := range atRisk {
+			fmt.Printf("   • %s\n", line)
+		}
+		fmt.Print("Type 'force' to remove it anyway: ")
+		if readAnswer() != "force" {
+			fmt.Printf("Keeping '%s'.\n", sess.ClonePath)
+			return nil
+		}
+		force = true
+	}
+
+	if worktree {
+		log.Debugf("🌿 Removing worktree '%s'...\n", sess.ClonePath)
+		return gitMgr.RemoveWorktree(sess.MirrorPath, sess.ClonePath, force)
+	}
+
+	log.Debugf("📁 Removing clone directory '%s'...\n", sess.ClonePath)
+	if err := os.RemoveAll(sess.ClonePath); err != nil {
+		return fmt.Errorf("failed to remove clone directory: %w", err)
+	}
+	return nil
+}
+
+// localWork describes, one line per repository, the local work that removing
+// the directory root would lose. Worktrees count like clones, except for
+// stashes: those are the mirror's shared refs/stash, which survives removing
+// the worktree.
+func localWork(gitMgr *git.Manager, root string) []string {
+	var atRisk []string
+	for _, path := range sessionRepoPaths(root) {
+		st, err := gitMgr.CheckRepoStatus(path)
+		if err != nil {
+			atRisk = append(atRisk, fmt.Sprintf("%s: could not inspect (%v)", path, err))
+			continue
+		}
+		if isWorktree(path) {
+			st.Stashes = 0
+		}
+		if !st.Clean() {
+			atRisk = append(atRisk, fmt.Sprintf("%s: %s", path, st.Summary()))
+		}
+	}
+	return atRisk
+}
+
+// readAnswer reads a single word from stdin, treating read errors as an
+// empty answer
+func readAnswer() string {
+	var answer string
+	if _, err := fmt.Scanln(&answer); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(answer)
+}
+
+// sessionRepoPaths returns the git checkouts within a session directory: the
+// directory itself for repository sessions, or each repository directly
+// below it for workspace sessions
+func sessionRepoPaths(root string) []string {
+	if isGitCheckout(root) {
+		return []string{root}
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		path := filepath.Join(root, entry.Name())
+		if entry.IsDir() && isGitCheckout(path) {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// isGitCheckout reports whether path has a .git directory, or a .git file
+// as worktrees do
+func isGitCheckout(path string) bool {
+	_, err := os.Stat(filepath.Join(path, ".git"))
+	return err == nil
+}
+
+// isWorktree reports whether the checkout at path is a linked worktree, whose
+// .git is a file pointing into the repository it was added to
+func isWorktree(path string) bool {
+	info, err := os.Stat(filepath.Join(path, ".git"))
+	return err == nil && !info.IsDir()
+}
+
+// isWithinDir reports whether path is strictly inside dir
+func isWithinDir(dir, path string) bool {
+	if dir == "" {
+		return false
+	}
+	rel, err := filepath.Rel(filepath.Clean(dir), filepath.Clean(path))
+	if err != nil {
+		return false
+	}
+	return rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
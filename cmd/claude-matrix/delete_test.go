@@ -0,0 +1,112 @@
+package main
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/mateimicu/tmux-claude-matrix/internal/git"
+)
+
+func TestSessionRepoPaths(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	repo := filepath.Join(tmpDir, "repo")
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got := sessionRepoPaths(repo); !reflect.DeepEqual(got, []string{repo}) {
+		t.Errorf("sessionRepoPaths(repo) = %v, want [%s]", got, repo)
+	}
+
+	// Workspace with a cloned repo, a worktree (.git file) and a plain directory
+	ws := filepath.Join(tmpDir, "ws")
+	if err := os.MkdirAll(filepath.Join(ws, "api", ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(ws, "web"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(ws, "web", ".git"), []byte("gitdir: /elsewhere"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(ws, "notes"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join(ws, "api"), filepath.Join(ws, "web")}
+	if got := sessionRepoPaths(ws); !reflect.DeepEqual(got, want) {
+		t.Errorf("sessionRepoPaths(ws) = %v, want %v", got, want)
+	}
+}
+
+func TestIsWithinDir(t *testing.T) {
+	tests := []struct {
+		dir, path string
+		want      bool
+	}{
+		{"/home/u/repos", "/home/u/repos/org-repo-123", true},
+		{"/home/u/repos", "/home/u/repos/ws/api", true},
+		{"/home/u/repos", "/home/u/repos", false},
+		{"/home/u/repos", "/home/u/repos/..", false},
+		{"/home/u/repos", "/home/u/repos-other/x", false},
+		{"/home/u/repos", "/home/u/repos/../../etc", false},
+		{"", "/home/u/repos/x", false},
+	}
+
+	for _, tt := range tests {
+		if got := isWithinDir(tt.dir, tt.path); got != tt.want {
+			t.Errorf("isWithinDir(%q, %q) = %v, want %v", tt.dir, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestLocalWorkCountsWorktreeCommits(t *testing.T) {
+	tmpDir := t.TempDir()
+	gitCmd := func(args ...string) {
+		t.Helper()
+		full := append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+		if out, err := exec.Command("git", full...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	source := filepath.Join(tmpDir, "source")
+	gitCmd("init", "-q", source)
+	gitCmd("-C", source, "commit", "-q", "--allow-empty", "-m", "init")
+
+	gitMgr := git.New()
+	gitMgr.SetOutput(io.Discard)
+	url := "file://" + source
+	cacheDir := filepath.Join(tmpDir, "cache")
+	if _, err := gitMgr.EnsureMirror(url, cacheDir); err != nil {
+		t.Fatal(err)
+	}
+	wt := filepath.Join(tmpDir, "wt")
+	if err := gitMgr.AddWorktree(gitMgr.GetMirrorPath(url, cacheDir), wt, "claude/s1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := localWork(gitMgr, wt); len(got) != 0 {
+		t.Errorf("localWork() of a fresh worktree = %v, want none", got)
+	}
+
+	// A stash is stored in the mirror, shared by all of its worktrees
+	other := filepath.Join(tmpDir, "other")
+	if err := gitMgr.AddWorktree(gitMgr.GetMirrorPath(url, cacheDir), other, "claude/s2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(other, "wip.txt"), []byte("wip\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	gitCmd("-C", other, "add", "wip.txt")
+	gitCmd("-C", other, "stash", "-q")
+	if got := localWork(gitMgr, wt); len(got) != 0 {
+		t.Errorf("localWork() should ignore the mirror's stashes, got %v", got)
+	}
+
+	gitCmd("-C", wt, "commit", "-q", "--allow-empty", "-m", "unpushed")
+	if got := localWork(gitMgr, wt); len(got) != 1 {
+		t.Errorf("localWork() should report the worktree's unpushed commit, got %v", got)
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mateimicu/tmux-claude-matrix/internal/gc"
+	"github.com/mateimicu/tmux-claude-matrix/internal/git"
+	"github.com/mateimicu/tmux-claude-matrix/internal/logging"
+	"github.com/mateimicu/tmux-claude-matrix/internal/session"
+	"github.com/mateimicu/tmux-claude-matrix/internal/status"
+	"github.com/mateimicu/tmux-claude-matrix/internal/tmux"
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+// gcMinAge keeps gc away from files and directories that are still being
+// written by a running hook or create
+const gcMinAge = 10 * time.Minute
+
+func gcCmd() *cobra.Command {
+	var apply, force bool
+
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Clean up leftover session metadata, status files and clones",
+		Long: `Cross-reference session metadata, tmux sessions, the status directory and
+the clone directory, and report what is left over: metadata whose clone is
+gone, state files of sessions that no longer exist, temp files from
+interrupted writes, expired name reservations and clone directories no
+session uses.
+
+Nothing is removed unless --apply is given. Clone directories with
+uncommitted changes, stashes or unpushed commits are kept unless --force is
+given as well.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGC(cmd.Context(), apply, force)
+		},
+	}
+
+	cmd.Flags().BoolVar(&apply, "apply", false, "Remove the reported items instead of only listing them")
+	cmd.Flags().BoolVar(&force, "force", false, "With --apply, also remove clone directories that contain local work")
+
+	return cmd
+}
+
+func runGC(ctx context.Context, apply, force bool) error {
+	cfg := configFromContext(ctx)
+	log := loggerFromContext(ctx)
+
+	sessionMgr := session.NewManager(cfg.SessionsDir)
+	gitMgr := git.New()
+	tmuxMgr := tmux.New()
+
+	sessions, err := sessionMgr.List()
+	var corrupt *session.CorruptError
+	if errors.As(err, &corrupt) {
+		// The clone and state files of an unreadable session would look
+		// orphaned, so nothing is removed until the metadata is fixed
+		log.Warnf("⚠️  %v\n", corrupt)
+		if apply {
+			return fmt.Errorf("fix or remove the corrupt session files before running gc --apply")
+		}
+	} else if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+	tmuxSessions, err := tmuxMgr.ListSessions()
+	if err != nil {
+		return fmt.Errorf("failed to list tmux sessions: %w", err)
+	}
+
+	items, err := gc.Scan(gc.Options{
+		Sessions:       sessions,
+		TmuxSessions:   tmuxSessions,
+		StatusDir:      status.DefaultStatusDir(),
+		SessionsDir:    cfg.SessionsDir,
+		CloneDir:       cfg.CloneDir,
+		ReservationTTL: session.ReservationTTL,
+		MinAge:         gcMinAge,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan for leftovers: %w", err)
+	}
+
+	if len(items) == 0 {
+		fmt.Println("✓ Nothing to clean up")
+		return nil
+	}
+
+	removed, kept := 0, 0
+	var lastKind gc.Kind
+	for _, item := range items {
+		if item.Kind != lastKind {
+			fmt.Printf("\n%s\n", gcKindHeading(item.Kind))
+			lastKind = item.Kind
+		}
+
+		label := item.Path
+		if item.Kind == gc.KindMetadata {
+			label = fmt.Sprintf("%s (clone was %s)", item.Session.Name, item.Path)
+		}
+
+		var atRisk []string
+		if item.Kind == gc.KindCloneDir {
+			atRisk = localWork(gitMgr, item.Path)
+		}
+
+		fmt.Printf("  • %s\n", label)
+		for _, line := range atRisk {
+			fmt.Printf("      ⚠️  %s\n", line)
+		}
+
+		if !apply {
+			continue
+		}
+		if len(atRisk) > 0 && !force {
+			fmt.Printf("      kept, use --force to remove\n")
+			kept++
+			continue
+		}
+		if err := removeGCItem(cfg, sessionMgr, gitMgr, item, log); err != nil {
+			log.Warnf("⚠️  Failed to remove %s: %v\n", item.Path, err)
+			kept++
+			continue
+		}
+		removed++
+	}
+	fmt.Println()
+
+	if !apply {
+		fmt.Printf("Found %d item(s). Run 'claude-matrix gc --apply' to remove them.\n", len(items))
+		return nil
+	}
+
+	// User-facing success confirmation — always visible
+	fmt.Printf("✓ Removed %d item(s)", removed)
+	if kept > 0 {
+		fmt.Printf(", kept %d", kept)
+	}
+	fmt.Println()
+	return nil
+}
+
+// gcKindHeading returns the report heading for a kind of leftover
+func gcKindHeading(kind gc.Kind) string {
+	switch kind {
+	case gc.KindMetadata:
+		return "🗂️  Sessions whose clone directory is gone:"
+	case gc.KindStatusFile:
+		return "📄 State files of sessions that no longer exist:"
+	case gc.KindTempFile:
+		return "🧹 Temp files from interrupted writes:"
+	case gc.KindReservation:
+		return "🔖 Expired reservations of unfinished creates:"
+	case gc.KindCloneDir:
+		return "📁 Clone directories no session uses:"
+	default:
+		return string(kind) + ":"
+	}
+}
+
+// removeGCItem removes a single leftover reported by gc.Scan
+func removeGCItem(cfg *types.Config, sessionMgr *session.Manager, gitMgr *git.Manager, item gc.Item, log *logging.Logger) error {
+	switch item.Kind {
+	case gc.KindMetadata:
+		sess := item.Session
+		if sess.MirrorPath != "" {
+			// Prunes the worktree entry the missing directory left in the mirror
+			if err := gitMgr.RemoveWorktree(sess.MirrorPath, sess.ClonePath, false); err != nil {
+				log.Warnf("⚠️  Failed to prune worktree of '%s': %v\n", sess.Name, err)
+			}
+		}
+		statusDir := status.DefaultStatusDir()
+		status.RemoveAllAgentStates(statusDir, sess.Name) //nolint:errcheck // Best-effort cleanup
+		status.RemoveState(statusDir, sess.Name)          //nolint:errcheck // Best-effort cleanup
+		return sessionMgr.Delete(sess.Name)
+
+	case gc.KindStatusFile, gc.KindTempFile, gc.KindReservation:
+		// Removing stale metadata may already have taken its state files
+		if err := os.Remove(item.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+
+	case gc.KindCloneDir:
+		if !isWithinDir(cfg.CloneDir, item.Path) {
+			return fmt.Errorf("refusing to remove %s outside %s", item.Path, cfg.CloneDir)
+		}
+		if mirrorPath, ok := gitMgr.WorktreeMirror(item.Path); ok {
+			return gitMgr.RemoveWorktree(mirrorPath, item.Path, true)
+		}
+		return os.RemoveAll(item.Path)
+
+	default:
+		return fmt.Errorf("unknown item kind %q", item.Kind)
+	}
+}
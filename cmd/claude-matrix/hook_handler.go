@@ -1,11 +1,14 @@
 package main
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
 
 	"github.com/mateimicu/tmux-claude-matrix/internal/hooks"
+	"github.com/mateimicu/tmux-claude-matrix/internal/session"
+	"github.com/mateimicu/tmux-claude-matrix/internal/status"
 	"github.com/mateimicu/tmux-claude-matrix/internal/tmux"
 )
 
@@ -15,7 +18,15 @@ func hookHandlerCmd() *cobra.Command {
 		Short:  "Handle Claude Code hook events (internal use)",
 		Hidden: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return hooks.HandleHookEvent(os.Stdin, tmux.New())
+			cfg := configFromContext(cmd.Context())
+			tmuxMgr := tmux.New()
+
+			notifier, err := hooks.NewNotifier(cfg, tmuxMgr, status.DefaultStatusDir())
+			if err != nil {
+				// A bad notify config must not break state tracking
+				fmt.Fprintf(os.Stderr, "claude-matrix: notifications disabled: %v\n", err)
+			}
+			return hooks.HandleHookEvent(os.Stdin, tmuxMgr, session.NewManager(cfg.SessionsDir), notifier)
 		},
 	}
 	// The --from flag is used as a marker in the registered hook command
@@ -10,10 +10,12 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/mateimicu/tmux-claude-matrix/internal/fzf"
+	"github.com/mateimicu/tmux-claude-matrix/internal/git"
 	"github.com/mateimicu/tmux-claude-matrix/internal/logging"
 	"github.com/mateimicu/tmux-claude-matrix/internal/session"
 	"github.com/mateimicu/tmux-claude-matrix/internal/status"
 	"github.com/mateimicu/tmux-claude-matrix/internal/tmux"
+	"github.com/mateimicu/tmux-claude-matrix/internal/uistate"
 	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
 )
 
@@ -33,20 +35,33 @@ func runList(ctx context.Context) error {
 	log := loggerFromContext(ctx)
 
 	sessionMgr := session.NewManager(cfg.SessionsDir)
+	gitMgr := git.New()
 	tmuxMgr := tmux.New()
 
-	// Toggle state for hiding inactive sessions (resets each invocation)
-	showActiveOnly := false
+	// Binary path for the FZF preview pane
+	binaryPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to get binary path: %w", err)
+	}
+	preview := fzf.PreviewOptions{
+		BinaryPath: binaryPath,
+		Window:     cfg.PreviewWindow,
+		ScrollUp:   cfg.PreviewScrollUp,
+		ScrollDown: cfg.PreviewScrollDown,
+	}
+
+	// Filter state resets each invocation; the sort mode is remembered
+	view := fzf.SessionView{SortMode: fzf.ParseSortMode(uistate.Load(cfg.UIStateFile).SortMode)}
 
 	// Main loop - continue showing list until user exits or switches
 	for {
-		// Load sessions
-		sessions, err := sessionMgr.List()
+		// Build session status list
+		statusList, err := loadSessionStatuses(ctx, cfg, log, sessionMgr, tmuxMgr)
 		if err != nil {
-			return fmt.Errorf("failed to list sessions: %w", err)
+			return err
 		}
 
-		if len(sessions) == 0 {
+		if len(statusList) == 0 {
 			fmt.Println("No sessions found. Create one with: claude-matrix create")
 			fmt.Print("\nPress Enter to close...")
 			//nolint:errcheck // intentionally ignoring - just waiting for keypress
@@ -54,52 +69,31 @@ func runList(ctx context.Context) error {
 			return nil
 		}
 
-		// Get tmux status
-		activeSessions, err := tmuxMgr.ListSessions()
-		if err != nil {
-			return fmt.Errorf("failed to list tmux sessions: %w", err)
-		}
-		activeMap := make(map[string]bool)
-		for _, name := range activeSessions {
-			activeMap[name] = true
-		}
-
-		// Build session status list
-		var statusList []*types.SessionStatus
-		for _, sess := range sessions {
-			sessStatus := &types.SessionStatus{
-				Session:       sess,
-				TmuxActive:    activeMap[sess.Name],
-				ClaudeRunning: false,
-				ClaudeState:   types.ClaudeStateStopped,
-			}
-
-			// Check Claude status if session is active
-			if sessStatus.TmuxActive {
-				sessStatus.ClaudeRunning = tmuxMgr.GetClaudeStatus(sess.Name)
-				// Get detailed state
-				state, lastActivity := tmuxMgr.GetDetailedClaudeState(sess.Name)
-				sessStatus.ClaudeState = state
-				sessStatus.LastActivity = lastActivity
-			}
-
-			statusList = append(statusList, sessStatus)
-		}
-
 		// Apply active-only filter if toggled on
 		displayList := statusList
-		if showActiveOnly {
+		if view.ShowActiveOnly {
 			filtered := fzf.FilterActiveSessions(statusList)
 			if len(filtered) == 0 {
-				showActiveOnly = false
+				view.ShowActiveOnly = false
 				log.Warnf("⚠️  No active sessions to filter, showing all sessions.\n")
 			} else {
 				displayList = filtered
 			}
 		}
 
+		// Apply tag filter if one is selected
+		if view.TagFilter != "" {
+			filtered := fzf.FilterSessionsByTag(displayList, view.TagFilter)
+			if len(filtered) == 0 {
+				log.Warnf("⚠️  No sessions tagged #%s, showing all sessions.\n", view.TagFilter)
+				view.TagFilter = ""
+			} else {
+				displayList = filtered
+			}
+		}
+
 		// Show FZF selection with action support
-		selection, err := fzf.SelectSessionWithAction(displayList, showActiveOnly)
+		selection, err := fzf.SelectSessionWithAction(displayList, view, preview)
 		if err != nil {
 			return fmt.Errorf("session selection cancelled: %w", err)
 		}
@@ -107,7 +101,22 @@ func runList(ctx context.Context) error {
 		// Handle action
 		switch selection.Action {
 		case fzf.SessionActionToggleFilter:
-			showActiveOnly = !showActiveOnly
+			view.ShowActiveOnly = !view.ShowActiveOnly
+			continue
+
+		case fzf.SessionActionCycleSort:
+			view.SortMode = view.SortMode.Next()
+			if err := uistate.Save(cfg.UIStateFile, &uistate.State{SortMode: string(view.SortMode)}); err != nil {
+				log.Warnf("⚠️  Failed to remember sort mode: %v\n", err)
+			}
+			continue
+
+		case fzf.SessionActionTagFilter:
+			tag, err := fzf.SelectTag(statusList, view.TagFilter)
+			if err != nil {
+				log.Warnf("⚠️  %v\n", err)
+			}
+			view.TagFilter = tag
 			continue
 
 		case fzf.SessionActionTools:
@@ -117,7 +126,7 @@ func runList(ctx context.Context) error {
 			continue
 
 		case fzf.SessionActionDelete:
-			if err := handleDeleteAction(sessionMgr, tmuxMgr, selection.Session, log); err != nil {
+			if err := handleDeleteAction(cfg, sessionMgr, gitMgr, tmuxMgr, selection.Session, log); err != nil {
 				log.Warnf("⚠️  Failed to delete session: %v\n", err)
 			}
 			// Continue loop to show updated list
@@ -128,6 +137,24 @@ func runList(ctx context.Context) error {
 			}
 			// Continue loop to show updated list
 
+		case fzf.SessionActionTag:
+			if err := handleTagAction(sessionMgr, selection.Session); err != nil {
+				fmt.Printf("⚠️  Failed to tag session: %v\n", err)
+			}
+			// Continue loop to show updated list
+
+		case fzf.SessionActionNotes:
+			if err := editNotes(sessionMgr, selection.Session.Session.Name); err != nil {
+				fmt.Printf("⚠️  Failed to edit notes: %v\n", err)
+			}
+			// Continue loop to show updated list
+
+		case fzf.SessionActionBroadcast:
+			if err := handleBroadcastAction(tmuxMgr, selection.Sessions); err != nil {
+				fmt.Printf("⚠️  Broadcast failed: %v\n", err)
+			}
+			// Continue loop to show updated list
+
 		case fzf.SessionActionSwitch:
 			if err := handleSwitchAction(cfg, tmuxMgr, selection.Session, log); err != nil {
 				return err
@@ -141,6 +168,28 @@ func runList(ctx context.Context) error {
 	}
 }
 
+// buildSessionStatus returns the runtime status of a session. The Claude
+// state is only probed when its tmux session is active.
+func buildSessionStatus(tmuxMgr *tmux.Manager, sess *types.Session, tmuxActive bool) *types.SessionStatus {
+	sessStatus := &types.SessionStatus{
+		Session:       sess,
+		TmuxActive:    tmuxActive,
+		ClaudeRunning: false,
+		ClaudeState:   types.ClaudeStateStopped,
+	}
+
+	// Check Claude status if session is active
+	if sessStatus.TmuxActive {
+		sessStatus.ClaudeRunning = tmuxMgr.GetClaudeStatus(sess.Name)
+		// Get detailed state
+		state, lastActivity := tmuxMgr.GetDetailedClaudeState(sess.Name)
+		sessStatus.ClaudeState = state
+		sessStatus.LastActivity = lastActivity
+	}
+
+	return sessStatus
+}
+
 func handleToolsAction(ctx context.Context, cfg *types.Config) error {
 	action, err := fzf.SelectToolAction()
 	if err != nil {
@@ -155,18 +204,12 @@ func handleToolsAction(ctx context.Context, cfg *types.Config) error {
 	}
 }
 
-func handleDeleteAction(sessionMgr *session.Manager, tmuxMgr *tmux.Manager, selected *types.SessionStatus, log *logging.Logger) error {
+func handleDeleteAction(cfg *types.Config, sessionMgr *session.Manager, gitMgr *git.Manager, tmuxMgr *tmux.Manager, selected *types.SessionStatus, log *logging.Logger) error {
 	sess := selected.Session
 
 	// Ask for confirmation
 	fmt.Printf("\n🗑️  Delete session '%s'? (y/N): ", sess.Name)
-	var confirmation string
-	if _, err := fmt.Scanln(&confirmation); err != nil {
-		// Treat read errors as cancellation
-		confirmation = "n"
-	}
-
-	if confirmation != "y" && confirmation != "Y" {
+	if confirmation := readAnswer(); confirmation != "y" && confirmation != "Y" {
 		fmt.Println("Deletion cancelled.")
 		return nil
 	}
@@ -179,6 +222,11 @@ func handleDeleteAction(sessionMgr *session.Manager, tmuxMgr *tmux.Manager, sele
 		}
 	}
 
+	// Remove the checkout, refusing to drop local work unless forced
+	if err := removeSessionCheckout(cfg, gitMgr, sess, log); err != nil {
+		log.Warnf("⚠️  Failed to remove '%s', leaving it in place: %v\n", sess.ClonePath, err)
+	}
+
 	// Delete metadata
 	if err := sessionMgr.Delete(sess.Name); err != nil {
 		return fmt.Errorf("failed to delete session metadata: %w", err)
@@ -194,6 +242,37 @@ func handleDeleteAction(sessionMgr *session.Manager, tmuxMgr *tmux.Manager, sele
 	return nil
 }
 
+func handleTagAction(sessionMgr *session.Manager, selected *types.SessionStatus) error {
+	current := strings.Join(selected.Session.Tags, " ")
+	fmt.Printf("\n🏷️  Tags of session '%s' (current: %q)\n", selected.Session.Name, current)
+	fmt.Print("Enter tags separated by spaces (empty to keep, '-' to clear): ")
+
+	scanner := bufio.NewScanner(os.Stdin)
+	if !scanner.Scan() {
+		return nil
+	}
+	input := strings.TrimSpace(scanner.Text())
+	if input == "" {
+		fmt.Println("Tags unchanged.")
+		return nil
+	}
+
+	var tags []string
+	if input != "-" {
+		tags = session.ParseTags(input)
+	}
+	err := sessionMgr.Update(selected.Session.Name, func(sess *types.Session) error {
+		sess.Tags = tags
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save session metadata: %w", err)
+	}
+
+	fmt.Printf("✓ Session '%s' tagged: %s\n\n", selected.Session.Name, strings.Join(tags, " "))
+	return nil
+}
+
 func handleSwitchAction(cfg *types.Config, tmuxMgr *tmux.Manager, selected *types.SessionStatus, log *logging.Logger) error {
 	// Switch to session
 	log.Debugf("🚀 Switching to session '%s'...\n", selected.Session.Name)
@@ -202,12 +281,9 @@ func handleSwitchAction(cfg *types.Config, tmuxMgr *tmux.Manager, selected *type
 	if !selected.TmuxActive {
 		log.Warnf("⚠️  Session not active, recreating...\n")
 
-		var claudeCmd string
-		if cfg.ClaudeBin != "" {
-			claudeCmd = cfg.ClaudeBin + " " + strings.Join(cfg.ClaudeArgs, " ")
-		}
+		claudeCmd := buildRecreateCommand(cfg, selected.Session)
 
-		if err := tmuxMgr.CreateSession(selected.Session.Name, selected.Session.ClonePath, claudeCmd); err != nil {
+		if err := startTmuxSession(cfg, tmuxMgr, selected.Session.Name, selected.Session.ClonePath, claudeCmd, selected.Session.Layout); err != nil {
 			return fmt.Errorf("failed to recreate session: %w", err)
 		}
 	}
@@ -227,6 +303,22 @@ func handleSwitchAction(cfg *types.Config, tmuxMgr *tmux.Manager, selected *type
 	return nil
 }
 
+// buildRecreateCommand returns the shell command that restarts Claude in a
+// recreated session, resuming its last conversation, or continuing the most
+// recent one in the clone when no conversation ID is known. The initial prompt
+// is only given to Claude when the session is first created.
+func buildRecreateCommand(cfg *types.Config, sess *types.Session) string {
+	claudeCmd := buildClaudeCommand(cfg, "")
+	if claudeCmd == "" {
+		return ""
+	}
+
+	if sess.ClaudeSessionID != "" {
+		return claudeCmd + " --resume " + tmux.ShellQuote(sess.ClaudeSessionID)
+	}
+	return claudeCmd + " --continue"
+}
+
 func handleRenameAction(sessionMgr *session.Manager, tmuxMgr *tmux.Manager, selected *types.SessionStatus) error {
 	fmt.Printf("\n✏️  Rename session '%s' (current title: %q)\n", selected.Session.Name, selected.Session.Title)
 	fmt.Print("Enter new title (empty to cancel): ")
@@ -242,8 +334,11 @@ func handleRenameAction(sessionMgr *session.Manager, tmuxMgr *tmux.Manager, sele
 	}
 
 	// Update title in session metadata
-	selected.Session.Title = newTitle
-	if err := sessionMgr.Save(selected.Session); err != nil {
+	err := sessionMgr.Update(selected.Session.Name, func(sess *types.Session) error {
+		sess.Title = newTitle
+		return nil
+	})
+	if err != nil {
 		return fmt.Errorf("failed to save session metadata: %w", err)
 	}
 
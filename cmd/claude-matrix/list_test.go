@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+func TestBuildRecreateCommand(t *testing.T) {
+	cfg := &types.Config{
+		ClaudeBin:  "claude",
+		ClaudeArgs: []string{"--dangerously-skip-permissions"},
+	}
+
+	tests := []struct {
+		name     string
+		cfg      *types.Config
+		sess     *types.Session
+		expected string
+	}{
+		{
+			name:     "known conversation is resumed",
+			cfg:      cfg,
+			sess:     &types.Session{ClaudeSessionID: "sess-abc-123", Prompt: "fix it"},
+			expected: "claude --dangerously-skip-permissions --resume 'sess-abc-123'",
+		},
+		{
+			name:     "unknown conversation is continued",
+			cfg:      cfg,
+			sess:     &types.Session{},
+			expected: "claude --dangerously-skip-permissions --continue",
+		},
+		{
+			name:     "initial prompt is not repeated",
+			cfg:      cfg,
+			sess:     &types.Session{Prompt: "fix it"},
+			expected: "claude --dangerously-skip-permissions --continue",
+		},
+		{
+			name:     "no claude binary",
+			cfg:      &types.Config{},
+			sess:     &types.Session{ClaudeSessionID: "sess-abc-123"},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildRecreateCommand(tt.cfg, tt.sess)
+			if got != tt.expected {
+				t.Errorf("buildRecreateCommand() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -84,11 +84,24 @@ It helps you quickly create development environments for your repositories.`,
 	rootCmd.AddCommand(
 		createCmd(),
 		listCmd(),
+		nextCmd(),
+		statusLineCmd(),
 		listReposCmd(),
 		renameCmd(),
+		tagCmd(),
+		notesCmd(),
 		diagnoseCmd(),
+		gcCmd(),
+		archiveCmd(),
+		restoreCmd(),
+		migrateCmd(),
 		refreshCmd(),
+		dashboardCmd(),
+		boardCmd(),
+		sendCmd(),
+		daemonCmd(),
 		hookHandlerCmd(),
+		previewCmd(),
 		setupHooksCmd(),
 		removeHooksCmd(),
 		versionCmd(),
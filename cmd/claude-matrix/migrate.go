@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mateimicu/tmux-claude-matrix/internal/session"
+)
+
+func migrateCmd() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Upgrade session metadata to the current format",
+		Long: `Upgrade every session metadata file to the current schema version and
+report what changed. Sessions are also upgraded when they are loaded; this
+command does it for all of them at once.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMigrate(cmd.Context(), dryRun)
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Report the changes without writing them")
+
+	return cmd
+}
+
+func runMigrate(ctx context.Context, dryRun bool) error {
+	cfg := configFromContext(ctx)
+	log := loggerFromContext(ctx)
+
+	sessionMgr := session.NewManager(cfg.SessionsDir)
+	results, err := sessionMgr.Migrate(dryRun)
+	var corrupt *session.CorruptError
+	if errors.As(err, &corrupt) {
+		log.Warnf("⚠️  %v\n", corrupt)
+	} else if err != nil {
+		return fmt.Errorf("failed to migrate sessions: %w", err)
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("✓ All sessions are at schema version %d\n", session.CurrentSchemaVersion)
+		return nil
+	}
+
+	for _, r := range results {
+		fmt.Printf("• %s: v%d → v%d\n", r.Name, r.From, r.To)
+		if len(r.Changes) == 0 {
+			fmt.Println("    schema version recorded, no fields changed")
+		}
+		for _, change := range r.Changes {
+			fmt.Printf("    %s\n", change)
+		}
+	}
+
+	if dryRun {
+		fmt.Printf("\n%d session(s) would be upgraded. Run without --dry-run to apply.\n", len(results))
+		return nil
+	}
+	// User-facing success confirmation — always visible
+	fmt.Printf("\n✓ Upgraded %d session(s)\n", len(results))
+	return nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mateimicu/tmux-claude-matrix/internal/status"
+	"github.com/mateimicu/tmux-claude-matrix/internal/tmux"
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+func nextCmd() *cobra.Command {
+	var includeIdle bool
+
+	cmd := &cobra.Command{
+		Use:   "next",
+		Short: "Switch to the next session that needs attention",
+		Long: `Switch to the session that needs attention most: errors first, then
+sessions waiting for input, the longest waiting first. Running it again from
+that session moves on to the next one, so repeated presses cycle through all
+of them. Meant to be bound to a tmux key; it queries only the daemon or the
+status dir and never opens a picker.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runNext(cmd.Context(), includeIdle)
+		},
+	}
+
+	cmd.Flags().BoolVar(&includeIdle, "include-idle", false, "Also visit sessions where Claude finished and is ready for a new prompt")
+
+	return cmd
+}
+
+func runNext(ctx context.Context, includeIdle bool) error {
+	log := loggerFromContext(ctx)
+	tmuxMgr := tmux.New()
+
+	states, live, ok := statesFromDaemon(ctx, configFromContext(ctx))
+	if !ok {
+		var err error
+		states, err = status.ListStates(status.DefaultStatusDir())
+		if err != nil {
+			return fmt.Errorf("failed to read session states: %w", err)
+		}
+		liveSessions, err := tmuxMgr.ListSessions()
+		if err != nil {
+			return fmt.Errorf("failed to list tmux sessions: %w", err)
+		}
+		live = make(map[string]bool, len(liveSessions))
+		for _, name := range liveSessions {
+			live[name] = true
+		}
+	}
+
+	current, _ := getCurrentTmuxSession() //nolint:errcheck // Outside tmux nothing is skipped
+	queue := attentionQueue(states, live, includeIdle)
+	target := nextInQueue(queue, current)
+	if target == "" {
+		msg := "nothing needs attention"
+		if len(queue) > 0 {
+			msg = "nothing else needs attention"
+		}
+		if err := tmuxMgr.DisplayMessage("claude-matrix: " + msg); err != nil {
+			fmt.Println(msg)
+		}
+		return nil
+	}
+
+	log.Debugf("🚀 Switching to session '%s'...\n", target)
+	if err := tmuxMgr.SwitchToSession(target); err != nil {
+		return fmt.Errorf("failed to switch to session %q: %w", target, err)
+	}
+	return nil
+}
+
+// attentionQueue returns the live sessions that need attention in the order
+// they should be visited: by StatePriority, then the longest in its state
+// first
+func attentionQueue(states map[string]*status.StateFile, live map[string]bool, includeIdle bool) []string {
+	var queue []string
+	for name, sf := range states {
+		if !live[name] || !needsAttention(types.ClaudeState(sf.State), includeIdle) {
+			continue
+		}
+		queue = append(queue, name)
+	}
+
+	sort.Slice(queue, func(i, j int) bool {
+		a, b := states[queue[i]], states[queue[j]]
+		pa, pb := status.StatePriority(types.ClaudeState(a.State)), status.StatePriority(types.ClaudeState(b.State))
+		if pa != pb {
+			return pa > pb
+		}
+		if sa, sb := stateStart(a), stateStart(b); !sa.Equal(sb) {
+			return sa.Before(sb)
+		}
+		return queue[i] < queue[j]
+	})
+	return queue
+}
+
+// stateStart returns when a session entered its state. State files written
+// before Since was recorded only tell when they were last updated.
+func stateStart(sf *status.StateFile) time.Time {
+	if !sf.Since.IsZero() {
+		return sf.Since
+	}
+	return sf.UpdatedAt
+}
+
+// needsAttention reports whether a session in state is waiting for the user
+func needsAttention(state types.ClaudeState, includeIdle bool) bool {
+	switch state {
+	case types.ClaudeStateError, types.ClaudeStateWaitingForInput:
+		return true
+	case types.ClaudeStateIdle:
+		return includeIdle
+	default:
+		return false
+	}
+}
+
+// nextInQueue returns the session after current in queue, wrapping around,
+// or the head of the queue when current isn't in it. It never returns
+// current; an empty string means there is nowhere to go.
+func nextInQueue(queue []string, current string) string {
+	for i, name := range queue {
+		if name == current {
+			if len(queue) == 1 {
+				return ""
+			}
+			return queue[(i+1)%len(queue)]
+		}
+	}
+	if len(queue) == 0 {
+		return ""
+	}
+	return queue[0]
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mateimicu/tmux-claude-matrix/internal/status"
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+func TestAttentionQueue(t *testing.T) {
+	now := time.Now()
+	states := map[string]*status.StateFile{
+		"waiting-new": {State: string(types.ClaudeStateWaitingForInput), UpdatedAt: now.Add(-1 * time.Minute)},
+		"waiting-old": {State: string(types.ClaudeStateWaitingForInput), UpdatedAt: now.Add(-30 * time.Minute)},
+		"broken":      {State: string(types.ClaudeStateError), UpdatedAt: now},
+		// Updated just now, but waiting the longest
+		"waiting-long": {State: string(types.ClaudeStateWaitingForInput), UpdatedAt: now, Since: now.Add(-2 * time.Hour)},
+		"busy":         {State: string(types.ClaudeStateRunning), UpdatedAt: now.Add(-time.Hour)},
+		"done":         {State: string(types.ClaudeStateIdle), UpdatedAt: now.Add(-time.Hour)},
+		"gone":         {State: string(types.ClaudeStateError), UpdatedAt: now},
+	}
+	live := map[string]bool{"waiting-new": true, "waiting-old": true, "waiting-long": true, "broken": true, "busy": true, "done": true}
+
+	got := attentionQueue(states, live, false)
+	want := []string{"broken", "waiting-long", "waiting-old", "waiting-new"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("attentionQueue() = %v, want %v", got, want)
+	}
+
+	got = attentionQueue(states, live, true)
+	want = []string{"broken", "waiting-long", "waiting-old", "waiting-new", "done"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("attentionQueue(includeIdle) = %v, want %v", got, want)
+	}
+}
+
+func TestNextInQueue(t *testing.T) {
+	queue := []string{"a", "b", "c"}
+
+	tests := []struct {
+		name    string
+		queue   []string
+		current string
+		want    string
+	}{
+		{name: "outside the queue starts at the head", queue: queue, current: "other", want: "a"},
+		{name: "moves on from the current session", queue: queue, current: "a", want: "b"},
+		{name: "wraps around", queue: queue, current: "c", want: "a"},
+		{name: "only the current session", queue: []string{"a"}, current: "a", want: ""},
+		{name: "empty queue", queue: nil, current: "a", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextInQueue(tt.queue, tt.current); got != tt.want {
+				t.Errorf("nextInQueue(%v, %q) = %q, want %q", tt.queue, tt.current, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mateimicu/tmux-claude-matrix/internal/session"
+)
+
+func notesCmd() *cobra.Command {
+	var printOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "notes [session]",
+		Short: "Edit the notes of a session",
+		Long: `Open the free-form notes of a session in $VISUAL or $EDITOR. Notes are
+stored next to the session metadata and shown in the list preview. Without a
+session name, the current tmux session is used.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := ""
+			if len(args) > 0 {
+				name = args[0]
+			} else {
+				current, err := getCurrentTmuxSession()
+				if err != nil {
+					return fmt.Errorf("failed to detect current tmux session: %w", err)
+				}
+				name = current
+			}
+			return runNotes(cmd.Context(), name, printOnly)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&printOnly, "print", "p", false, "Print the notes instead of editing them")
+
+	return cmd
+}
+
+func runNotes(ctx context.Context, name string, printOnly bool) error {
+	cfg := configFromContext(ctx)
+	sessionMgr := session.NewManager(cfg.SessionsDir)
+
+	if _, err := sessionMgr.Load(name); err != nil {
+		return fmt.Errorf("session %q not found: %w", name, err)
+	}
+
+	if printOnly {
+		notes, err := sessionMgr.LoadNotes(name)
+		if err != nil {
+			return fmt.Errorf("failed to read notes: %w", err)
+		}
+		fmt.Print(notes)
+		return nil
+	}
+
+	return editNotes(sessionMgr, name)
+}
+
+// editNotes opens the notes of a session in the user's editor. Notes left
+// blank are removed.
+func editNotes(sessionMgr *session.Manager, name string) error {
+	path := sessionMgr.NotesPath(name)
+
+	// The editor may carry arguments (e.g. "code --wait"), so it runs
+	// through the shell with the path passed as $1
+	cmd := exec.Command("sh", "-c", editorCommand()+` "$1"`, "sh", path)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor failed: %w", err)
+	}
+
+	notes, err := sessionMgr.LoadNotes(name)
+	if err != nil {
+		return fmt.Errorf("failed to read notes: %w", err)
+	}
+	if strings.TrimSpace(notes) == "" {
+		return sessionMgr.SaveNotes(name, "")
+	}
+	return nil
+}
+
+// editorCommand returns the user's preferred editor, falling back to vi
+func editorCommand() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(env)); editor != "" {
+			return editor
+		}
+	}
+	return "vi"
+}
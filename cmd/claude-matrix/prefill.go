@@ -62,6 +62,7 @@ func runPrefillCache(ctx context.Context, cfg *types.Config) error {
 	fmt.Printf("Found %d repositories to cache.\n\n", len(urls))
 
 	gitMgr := git.New()
+	gitMgr.SetSessionBranchPattern(cfg.BranchPattern)
 	var newCount, updatedCount, failedCount int
 	total := len(urls)
 
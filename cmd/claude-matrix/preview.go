@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mateimicu/tmux-claude-matrix/internal/fzf"
+	"github.com/mateimicu/tmux-claude-matrix/internal/session"
+	"github.com/mateimicu/tmux-claude-matrix/internal/tmux"
+)
+
+func previewCmd() *cobra.Command {
+	var lines int
+
+	cmd := &cobra.Command{
+		Use:    "preview <session>",
+		Short:  "Print the session picker preview of a session (internal use)",
+		Hidden: true,
+		Args:   cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !cmd.Flags().Changed("lines") {
+				lines = configFromContext(cmd.Context()).PreviewLines
+			}
+			// FZF passes the "[name]" field of the selected line
+			name := strings.TrimSuffix(strings.TrimPrefix(args[0], "["), "]")
+			return runPreview(cmd.Context(), name, lines)
+		},
+	}
+
+	cmd.Flags().IntVarP(&lines, "lines", "n", 0, "Claude pane lines to show (default PREVIEW_LINES)")
+
+	return cmd
+}
+
+func runPreview(ctx context.Context, name string, lines int) error {
+	cfg := configFromContext(ctx)
+
+	sessionMgr := session.NewManager(cfg.SessionsDir)
+	tmuxMgr := tmux.New()
+
+	sess, err := sessionMgr.Load(name)
+	if err != nil {
+		return fmt.Errorf("session %q not found: %w", name, err)
+	}
+	notes, err := sessionMgr.LoadNotes(name)
+	if err != nil {
+		return fmt.Errorf("failed to read notes: %w", err)
+	}
+
+	sessStatus := buildSessionStatus(tmuxMgr, sess, tmuxMgr.SessionExists(name))
+
+	var pane string
+	if sessStatus.TmuxActive && lines > 0 {
+		// The preview is best-effort; details and notes are still useful
+		// when the pane can't be captured
+		pane, _ = tmuxMgr.CaptureClaudePane(name, lines)
+	}
+
+	fmt.Print(fzf.FormatSessionPreview(sessStatus, notes, pane, time.Now()))
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/mateimicu/tmux-claude-matrix/internal/session"
 	"github.com/mateimicu/tmux-claude-matrix/internal/tmux"
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
 )
 
 func renameCmd() *cobra.Command {
@@ -48,16 +49,16 @@ func runRename(ctx context.Context, title string) error {
 	cfg := configFromContext(ctx)
 	log := loggerFromContext(ctx)
 
-	// Load session metadata
+	// Update title in session metadata
 	sessionMgr := session.NewManager(cfg.SessionsDir)
-	sess, err := sessionMgr.Load(sessionName)
-	if err != nil {
-		return fmt.Errorf("session %q not found in metadata: %w", sessionName, err)
+	if !sessionMgr.Exists(sessionName) {
+		return fmt.Errorf("session %q not found in metadata", sessionName)
 	}
-
-	// Update title
-	sess.Title = title
-	if err := sessionMgr.Save(sess); err != nil {
+	err = sessionMgr.Update(sessionName, func(sess *types.Session) error {
+		sess.Title = title
+		return nil
+	})
+	if err != nil {
 		return fmt.Errorf("failed to save session metadata: %w", err)
 	}
 
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mateimicu/tmux-claude-matrix/internal/session"
+	"github.com/mateimicu/tmux-claude-matrix/internal/tmux"
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+type sendOptions struct {
+	file  string
+	enter bool
+	force bool
+}
+
+func sendCmd() *cobra.Command {
+	opts := &sendOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "send <session> [text...]",
+		Short: "Type text into a session's Claude pane",
+		Long: `Type text into the pane running Claude in a session without attaching to
+it. The text comes from the arguments or, with --file, from a file ("-" reads
+stdin); multi-line text is pasted as a whole. With --enter the text is also
+submitted.
+
+Sending is refused while Claude is running in the session, as the text would
+interrupt its work, unless --force is given.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			text := strings.Join(args[1:], " ")
+			if opts.file != "" {
+				if text != "" {
+					return fmt.Errorf("text arguments and --file are mutually exclusive")
+				}
+				var data []byte
+				var err error
+				if opts.file == "-" {
+					data, err = io.ReadAll(os.Stdin)
+				} else {
+					data, err = os.ReadFile(opts.file)
+				}
+				if err != nil {
+					return fmt.Errorf("failed to read text file: %w", err)
+				}
+				text = strings.TrimRight(string(data), "\n")
+			}
+			if strings.TrimSpace(text) == "" {
+				return fmt.Errorf("no text to send")
+			}
+			return runSend(cmd.Context(), args[0], text, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.file, "file", "", `Read the text from a file ("-" for stdin)`)
+	cmd.Flags().BoolVar(&opts.enter, "enter", false, "Press Enter after the text to submit it")
+	cmd.Flags().BoolVar(&opts.force, "force", false, "Send even while Claude is running")
+
+	return cmd
+}
+
+func runSend(ctx context.Context, name, text string, opts *sendOptions) error {
+	cfg := configFromContext(ctx)
+	log := loggerFromContext(ctx)
+
+	sessionMgr := session.NewManager(cfg.SessionsDir)
+	if !sessionMgr.Exists(name) {
+		return fmt.Errorf("session %q not found", name)
+	}
+	tmuxMgr := tmux.New()
+	if !tmuxMgr.SessionExists(name) {
+		return fmt.Errorf("tmux session %q is not running", name)
+	}
+
+	state := aggregateState(name)
+	if state == "" {
+		// No hooks have reported yet; fall back to inspecting the pane
+		state, _ = tmuxMgr.GetDetailedClaudeState(name)
+	}
+	if err := checkSendTarget(name, state, opts.force); err != nil {
+		return err
+	}
+	log.Debugf("Sending %d bytes to %s (state %s)\n", len(text), name, state)
+
+	if err := sendToSession(tmuxMgr, name, text, opts.enter); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Sent to session '%s'\n", name)
+	return nil
+}
+
+// checkSendTarget refuses to type into a session whose Claude is busy
+// unless forced
+func checkSendTarget(name string, state types.ClaudeState, force bool) error {
+	if state == types.ClaudeStateRunning && !force {
+		return fmt.Errorf("session %q is busy: Claude is running; wait for it to finish or use --force", name)
+	}
+	return nil
+}
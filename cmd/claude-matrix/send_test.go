@@ -0,0 +1,27 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+func TestCheckSendTarget(t *testing.T) {
+	tests := []struct {
+		state   types.ClaudeState
+		force   bool
+		wantErr bool
+	}{
+		{types.ClaudeStateRunning, false, true},
+		{types.ClaudeStateRunning, true, false},
+		{types.ClaudeStateIdle, false, false},
+		{types.ClaudeStateWaitingForInput, false, false},
+		{"", false, false},
+	}
+	for _, tt := range tests {
+		err := checkSendTarget("s", tt.state, tt.force)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("checkSendTarget(%q, force=%v) error = %v, wantErr %v", tt.state, tt.force, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mateimicu/tmux-claude-matrix/internal/status"
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+// statusLineStates are the states counted by status-line, in display order,
+// with the tmux color of each
+var statusLineStates = []struct {
+	state types.ClaudeState
+	color string
+}{
+	{types.ClaudeStateRunning, "green"},
+	{types.ClaudeStateWaitingForInput, "yellow"},
+	{types.ClaudeStateIdle, "blue"},
+	{types.ClaudeStateError, "red"},
+}
+
+func statusLineCmd() *cobra.Command {
+	var tmuxColors bool
+
+	cmd := &cobra.Command{
+		Use:   "status-line",
+		Short: "Print session counts per Claude state for the tmux status bar",
+		Long: `Print how many sessions are in each Claude state, e.g. "🟢3 ❓2 ⚠️1", for use
+in status-right. Only the daemon or the status dir is queried, so it is cheap
+enough to run at every status-interval. Prints nothing when no session has a
+state.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			states, _, ok := statesFromDaemon(cmd.Context(), configFromContext(cmd.Context()))
+			if !ok {
+				var err error
+				states, err = status.ListStates(status.DefaultStatusDir())
+				if err != nil {
+					return fmt.Errorf("failed to read session states: %w", err)
+				}
+			}
+
+			counts := make(map[types.ClaudeState]int)
+			for _, sf := range states {
+				counts[types.ClaudeState(sf.State)]++
+			}
+			if line := formatStatusLine(counts, tmuxColors); line != "" {
+				fmt.Println(line)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&tmuxColors, "tmux-colors", false, "Wrap each count in tmux #[fg=...] style codes")
+
+	return cmd
+}
+
+// formatStatusLine renders the non-zero state counts, optionally colored
+// with tmux style codes
+func formatStatusLine(counts map[types.ClaudeState]int, tmuxColors bool) string {
+	var parts []string
+	for _, s := range statusLineStates {
+		n := counts[s.state]
+		if n == 0 {
+			continue
+		}
+		part := fmt.Sprintf("%s%d", status.EmojiForState(s.state), n)
+		if tmuxColors {
+			part = fmt.Sprintf("#[fg=%s]%s#[default]", s.color, part)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+func TestFormatStatusLine(t *testing.T) {
+	counts := map[types.ClaudeState]int{
+		types.ClaudeStateError:           1,
+		types.ClaudeStateRunning:         3,
+		types.ClaudeStateWaitingForInput: 2,
+		types.ClaudeStateStopped:         4,
+	}
+
+	if got, want := formatStatusLine(counts, false), "🟢3 ❓2 ⚠️1"; got != want {
+		t.Errorf("formatStatusLine() = %q, want %q", got, want)
+	}
+
+	want := "#[fg=green]🟢3#[default] #[fg=yellow]❓2#[default] #[fg=red]⚠️1#[default]"
+	if got := formatStatusLine(counts, true); got != want {
+		t.Errorf("formatStatusLine(tmuxColors) = %q, want %q", got, want)
+	}
+
+	if got := formatStatusLine(map[types.ClaudeState]int{}, true); got != "" {
+		t.Errorf("formatStatusLine() with no sessions = %q, want empty", got)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mateimicu/tmux-claude-matrix/internal/session"
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+func tagCmd() *cobra.Command {
+	var remove, clearAll bool
+
+	cmd := &cobra.Command{
+		Use:   "tag <session> [tags...]",
+		Short: "Show or change the tags of a session",
+		Long: `Add tags to a session, or remove them with --remove. Tags can be given as
+separate arguments or comma-separated. Without tags, the session's current
+tags are printed.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if remove && clearAll {
+				return fmt.Errorf("--remove and --clear are mutually exclusive")
+			}
+			return runTag(cmd.Context(), args[0], session.ParseTags(args[1:]...), remove, clearAll)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&remove, "remove", "r", false, "Remove the given tags instead of adding them")
+	cmd.Flags().BoolVar(&clearAll, "clear", false, "Remove all tags")
+
+	return cmd
+}
+
+func runTag(ctx context.Context, name string, tags []string, remove, clearAll bool) error {
+	cfg := configFromContext(ctx)
+	sessionMgr := session.NewManager(cfg.SessionsDir)
+
+	if len(tags) == 0 && !clearAll {
+		sess, err := sessionMgr.Load(name)
+		if err != nil {
+			return fmt.Errorf("session %q not found: %w", name, err)
+		}
+		fmt.Println(strings.Join(sess.Tags, " "))
+		return nil
+	}
+
+	var updated []string
+	err := sessionMgr.Update(name, func(sess *types.Session) error {
+		switch {
+		case clearAll:
+			sess.Tags = nil
+		case remove:
+			sess.Tags = session.RemoveTags(sess.Tags, tags...)
+		default:
+			sess.Tags = session.AddTags(sess.Tags, tags...)
+		}
+		updated = sess.Tags
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to tag session %q: %w", name, err)
+	}
+
+	// User-facing success confirmation — always visible
+	if len(updated) == 0 {
+		fmt.Printf("✓ Session '%s' has no tags\n", name)
+	} else {
+		fmt.Printf("✓ Session '%s' tagged: %s\n", name, strings.Join(updated, " "))
+	}
+	return nil
+}
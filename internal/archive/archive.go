@@ -0,0 +1,218 @@
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+// FormatVersion is the version of the archive layout written by Write
+const FormatVersion = 1
+
+// manifestName is the archive entry holding the Manifest
+const manifestName = "manifest.json"
+
+// Manifest describes an archived session. It is stored as the first entry of
+// the archive, followed by the files the repos refer to.
+type Manifest struct {
+	Version    int            `json:"version"`
+	ArchivedAt time.Time      `json:"archived_at"`
+	Session    *types.Session `json:"session"` // Includes the Claude session ID
+	Notes      string         `json:"notes,omitempty"`
+	Repos      []*Repo        `json:"repos"`
+}
+
+// Repo is a single git checkout of the session
+type Repo struct {
+	Dir    string `json:"dir"` // Relative to the session's ClonePath, "." for repository sessions
+	URL    string `json:"url"`
+	Branch string `json:"branch,omitempty"` // Empty for a detached HEAD
+	Head   string `json:"head"`
+	Bundle string `json:"bundle,omitempty"` // Git bundle of local commits, empty when there are none
+	Patch  string `json:"patch,omitempty"`  // Uncommitted changes, empty when there are none
+}
+
+// Write creates a gzip-compressed tar archive at path holding the manifest
+// and the files the repos refer to. Bundle and Patch of each repo are local
+// file paths; they are stored under their base name.
+func Write(path string, m *Manifest) (err error) {
+	m.Version = FormatVersion
+
+	entries := make(map[string]string)
+	stored := *m
+	stored.Repos = make([]*Repo, len(m.Repos))
+	for i, r := range m.Repos {
+		rc := *r
+		if rc.Bundle != "" {
+			rc.Bundle = filepath.Base(r.Bundle)
+			entries[rc.Bundle] = r.Bundle
+		}
+		if rc.Patch != "" {
+			rc.Patch = filepath.Base(r.Patch)
+			entries[rc.Patch] = r.Patch
+		}
+		stored.Repos[i] = &rc
+	}
+	if len(entries) != countFiles(m.Repos) {
+		return fmt.Errorf("archive files must have distinct names")
+	}
+
+	manifest, err := json.MarshalIndent(&stored, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(path) //nolint:errcheck // Best-effort cleanup of a partial archive
+		}
+	}()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	if err := writeEntry(tw, manifestName, manifest); err != nil {
+		return err
+	}
+	for _, r := range stored.Repos {
+		for _, name := range []string{r.Bundle, r.Patch} {
+			if name == "" {
+				continue
+			}
+			data, err := os.ReadFile(entries[name])
+			if err != nil {
+				return err
+			}
+			if err := writeEntry(tw, name, data); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// Read extracts the archive at path into destDir and returns its manifest.
+// Bundle and Patch of each repo are rewritten to the extracted file paths.
+func Read(path, destDir string) (*Manifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("not a session archive: %w", err)
+	}
+	defer gz.Close()
+
+	var m *Manifest
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		if hdr.Name == manifestName {
+			m = &Manifest{}
+			if err := json.NewDecoder(tr).Decode(m); err != nil {
+				return nil, fmt.Errorf("failed to parse manifest: %w", err)
+			}
+			continue
+		}
+
+		// Entries are flat; Base keeps a crafted name from escaping destDir
+		out, err := os.Create(filepath.Join(destDir, filepath.Base(hdr.Name)))
+		if err != nil {
+			return nil, err
+		}
+		_, err = io.Copy(out, tr)
+		if cerr := out.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if m == nil {
+		return nil, fmt.Errorf("not a session archive: missing %s", manifestName)
+	}
+	if m.Version > FormatVersion {
+		return nil, fmt.Errorf("archive format version %d is newer than supported version %d", m.Version, FormatVersion)
+	}
+	if m.Session == nil {
+		return nil, fmt.Errorf("archive has no session metadata")
+	}
+
+	for _, r := range m.Repos {
+		for _, name := range []*string{&r.Bundle, &r.Patch} {
+			if *name == "" {
+				continue
+			}
+			local := filepath.Join(destDir, filepath.Base(*name))
+			if _, err := os.Stat(local); err != nil {
+				return nil, fmt.Errorf("archive is missing %s", *name)
+			}
+			*name = local
+		}
+	}
+
+	return m, nil
+}
+
+func writeEntry(tw *tar.Writer, name string, data []byte) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// countFiles counts the bundle and patch files the repos refer to
+func countFiles(repos []*Repo) int {
+	n := 0
+	for _, r := range repos {
+		if r.Bundle != "" {
+			n++
+		}
+		if r.Patch != "" {
+			n++
+		}
+	}
+	return n
+}
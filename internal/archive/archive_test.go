@@ -0,0 +1,95 @@
+package archive
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+func TestWriteRead(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	bundle := filepath.Join(tmpDir, "0.bundle")
+	patch := filepath.Join(tmpDir, "0.patch")
+	if err := os.WriteFile(bundle, []byte("bundle data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(patch, []byte("patch data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &Manifest{
+		ArchivedAt: time.Now(),
+		Session: &types.Session{
+			Name:            "org-repo",
+			ClonePath:       "/repos/org-repo",
+			ClaudeSessionID: "sess-abc-123",
+		},
+		Notes: "next: review the PR\n",
+		Repos: []*Repo{
+			{Dir: ".", URL: "git@github.com:org/repo.git", Branch: "claude/org-repo", Head: "abc", Bundle: bundle, Patch: patch},
+		},
+	}
+
+	path := filepath.Join(tmpDir, "out", "org-repo.tar.gz")
+	if err := Write(path, m); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	// Write leaves the caller's paths alone
+	if m.Repos[0].Bundle != bundle {
+		t.Errorf("Write() modified Bundle to %q", m.Repos[0].Bundle)
+	}
+
+	dest := filepath.Join(tmpDir, "extract")
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Read(path, dest)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	if got.Version != FormatVersion {
+		t.Errorf("Version = %d, want %d", got.Version, FormatVersion)
+	}
+	if got.Session.ClaudeSessionID != "sess-abc-123" {
+		t.Errorf("ClaudeSessionID = %q", got.Session.ClaudeSessionID)
+	}
+	if got.Notes != m.Notes {
+		t.Errorf("Notes = %q, want %q", got.Notes, m.Notes)
+	}
+	if len(got.Repos) != 1 {
+		t.Fatalf("got %d repos, want 1", len(got.Repos))
+	}
+	r := got.Repos[0]
+	if r.Branch != "claude/org-repo" || r.URL != "git@github.com:org/repo.git" {
+		t.Errorf("repo = %+v", r)
+	}
+	for file, want := range map[string]string{r.Bundle: "bundle data", r.Patch: "patch data"} {
+		if filepath.Dir(file) != dest {
+			t.Errorf("%s not extracted into %s", file, dest)
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != want {
+			t.Errorf("%s = %q, want %q", file, data, want)
+		}
+	}
+}
+
+func TestRead_NotAnArchive(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "bogus.tar.gz")
+	if err := os.WriteFile(path, []byte("not gzip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Read(path, tmpDir); err == nil {
+		t.Error("Read() should fail for a file that is not an archive")
+	}
+}
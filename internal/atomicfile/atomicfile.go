@@ -0,0 +1,40 @@
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Write writes data to path via a synced temp file in the same directory and
+// a rename, so a crash leaves either the old or the new content. Temp files
+// are named *.tmp; an interrupted write may leave one behind.
+func Write(path string, data []byte) error {
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), "*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmpFile.Name()
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()    //nolint:errcheck // Best-effort cleanup on write failure
+		os.Remove(tmpPath) //nolint:errcheck // Best-effort cleanup on write failure
+		return err
+	}
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()    //nolint:errcheck // Best-effort cleanup on sync failure
+		os.Remove(tmpPath) //nolint:errcheck // Best-effort cleanup on sync failure
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpPath) //nolint:errcheck // Best-effort cleanup on close failure
+		return err
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		os.Remove(tmpPath) //nolint:errcheck // Best-effort cleanup on chmod failure
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath) //nolint:errcheck // Best-effort cleanup on rename failure
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package board
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/mateimicu/tmux-claude-matrix/internal/status"
+	"github.com/mateimicu/tmux-claude-matrix/internal/tmux"
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+const (
+	// SessionName is the tmux session holding the board window
+	SessionName = "claude-matrix-board"
+	// WindowName is the name of the board window
+	WindowName = "waiting"
+	// Target addresses the board window
+	Target = SessionName + ":" + WindowName
+
+	// paneOption records on each board pane the session it shows; empty on
+	// the placeholder pane
+	paneOption = "@claude-matrix-board"
+
+	// DefaultInterval is how often Watch rereads the state files
+	DefaultInterval = time.Second
+)
+
+// placeholderCommand keeps the board window open while no session waits
+const placeholderCommand = `sh -c 'printf "No sessions are waiting for input.\n"; exec tail -f /dev/null'`
+
+// Tmux is the subset of tmux operations needed to maintain the board
+type Tmux interface {
+	SessionExists(name string) bool
+	CreateSessionWithWindow(name, path, windowName, command string) (string, error)
+	ListPanes(target, option string) ([]tmux.Pane, error)
+	NewPane(target, command string) (string, error)
+	RespawnPane(paneID, command string) error
+	KillPane(paneID string) error
+	SetPaneOption(paneID, option, value string) error
+	SetWindowOption(target, option, value string) error
+	SelectLayout(target, layout string) error
+}
+
+// Waiting returns the sessions whose aggregate state file says Claude is
+// waiting for input, sorted by name. Stale states are ignored, as in
+// GetDetailedClaudeState.
+func Waiting(statusDir string) ([]string, error) {
+	states, err := status.ListStates(statusDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name, sf := range states {
+		if types.ClaudeState(sf.State) == types.ClaudeStateWaitingForInput && !status.IsStale(sf, status.DefaultStaleThreshold) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Sync makes the board window show exactly the waiting sessions, one tiled
+// pane each, creating the board session when needed. Panes of sessions that
+// stopped waiting are reused or closed; when none remain a placeholder pane
+// keeps the window open.
+func Sync(t Tmux, waiting []string) error {
+	if !t.SessionExists(SessionName) {
+		home, _ := os.UserHomeDir() //nolint:errcheck // Falls back to tmux's default directory
+		if _, err := t.CreateSessionWithWindow(SessionName, home, WindowName, placeholderCommand); err != nil {
+			return fmt.Errorf("failed to create board session: %w", err)
+		}
+		// Keeps panes whose attach exited so they can be respawned instead
+		// of closing the window with its last pane
+		if err := t.SetWindowOption(Target, "remain-on-exit", "on"); err != nil {
+			return fmt.Errorf("failed to configure board window: %w", err)
+		}
+	}
+
+	panes, err := t.ListPanes(Target, paneOption)
+	if err != nil {
+		return fmt.Errorf("failed to list board panes: %w", err)
+	}
+
+	want := make(map[string]bool)
+	for _, name := range waiting {
+		if name != SessionName && t.SessionExists(name) {
+			want[name] = true
+		}
+	}
+
+	shown := make(map[string]bool)
+	var spare []tmux.Pane
+	changed := false
+	for _, p := range panes {
+		if p.Option == "" || !want[p.Option] || shown[p.Option] {
+			spare = append(spare, p)
+			continue
+		}
+		shown[p.Option] = true
+		if p.Dead {
+			// The nested client detached or lost its session; attach again
+			if err := t.RespawnPane(p.ID, attachCommand(p.Option)); err != nil {
+				return fmt.Errorf("failed to reattach %s: %w", p.Option, err)
+			}
+		}
+	}
+
+	var missing []string
+	for name := range want {
+		if !shown[name] {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+
+	for _, name := range missing {
+		paneID := ""
+		if len(spare) > 0 {
+			// Reusing a pane never leaves the window without one
+			paneID, spare = spare[0].ID, spare[1:]
+			if err := t.RespawnPane(paneID, attachCommand(name)); err != nil {
+				return fmt.Errorf("failed to show %s: %w", name, err)
+			}
+		} else {
+			if paneID, err = t.NewPane(Target, attachCommand(name)); err != nil {
+				return fmt.Errorf("failed to show %s: %w", name, err)
+			}
+		}
+		if err := t.SetPaneOption(paneID, paneOption, name); err != nil {
+			return fmt.Errorf("failed to tag pane of %s: %w", name, err)
+		}
+		// Making room before the next split
+		if err := t.SelectLayout(Target, "tiled"); err != nil {
+			return fmt.Errorf("failed to tile board: %w", err)
+		}
+		changed = true
+	}
+
+	if len(want) == 0 && len(spare) > 0 {
+		// The window's last pane turns into the placeholder
+		p := spare[0]
+		spare = spare[1:]
+		if p.Option != "" || p.Dead {
+			if err := t.RespawnPane(p.ID, placeholderCommand); err != nil {
+				return fmt.Errorf("failed to reset board: %w", err)
+			}
+			if err := t.SetPaneOption(p.ID, paneOption, ""); err != nil {
+				return fmt.Errorf("failed to reset board: %w", err)
+			}
+		}
+	}
+	for _, p := range spare {
+		if err := t.KillPane(p.ID); err != nil {
+			return fmt.Errorf("failed to close board pane: %w", err)
+		}
+		changed = true
+	}
+
+	if changed {
+		if err := t.SelectLayout(Target, "tiled"); err != nil {
+			return fmt.Errorf("failed to tile board: %w", err)
+		}
+	}
+	return nil
+}
+
+// Watch keeps the board in sync with the state files in statusDir until
+// ctx is cancelled or the board session is closed. Only an unreadable status
+// dir ends it early.
+func Watch(ctx context.Context, t Tmux, statusDir string, interval time.Duration) error {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if !t.SessionExists(SessionName) {
+			return nil
+		}
+		waiting, err := Waiting(statusDir)
+		if err != nil {
+			return fmt.Errorf("failed to read session states: %w", err)
+		}
+		// A session closing mid-sync fails a tmux call; the next tick
+		// starts from the panes that actually exist
+		Sync(t, waiting) //nolint:errcheck // Retried on the next tick
+	}
+}
+
+// attachCommand runs a nested tmux client attached to session. TMUX is
+// unset because tmux refuses to nest otherwise.
+func attachCommand(session string) string {
+	return "env -u TMUX tmux attach-session -t " + tmux.ShellQuote("="+session)
+}
@@ -0,0 +1,201 @@
+package board
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/mateimicu/tmux-claude-matrix/internal/status"
+	"github.com/mateimicu/tmux-claude-matrix/internal/tmux"
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+// fakeTmux keeps the board window in memory. Like tmux, closing the last
+// pane closes the session.
+type fakeTmux struct {
+	sessions map[string]bool
+	panes    []*fakePane
+	nextID   int
+}
+
+type fakePane struct {
+	id, command, option string
+	dead                bool
+}
+
+func newFakeTmux(sessions ...string) *fakeTmux {
+	f := &fakeTmux{sessions: make(map[string]bool)}
+	for _, s := range sessions {
+		f.sessions[s] = true
+	}
+	return f
+}
+
+func (f *fakeTmux) addPane(command string) string {
+	f.nextID++
+	p := &fakePane{id: fmt.Sprintf("%%%d", f.nextID), command: command}
+	f.panes = append(f.panes, p)
+	return p.id
+}
+
+func (f *fakeTmux) pane(id string) (*fakePane, error) {
+	for _, p := range f.panes {
+		if p.id == id {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("can't find pane %s", id)
+}
+
+func (f *fakeTmux) SessionExists(name string) bool { return f.sessions[name] }
+
+func (f *fakeTmux) CreateSessionWithWindow(name, path, windowName, command string) (string, error) {
+	f.sessions[name] = true
+	f.addPane(command)
+	return "@1", nil
+}
+
+func (f *fakeTmux) ListPanes(target, option string) ([]tmux.Pane, error) {
+	var panes []tmux.Pane
+	for _, p := range f.panes {
+		panes = append(panes, tmux.Pane{ID: p.id, Dead: p.dead, Option: p.option})
+	}
+	return panes, nil
+}
+
+func (f *fakeTmux) NewPane(target, command string) (string, error) {
+	return f.addPane(command), nil
+}
+
+func (f *fakeTmux) RespawnPane(paneID, command string) error {
+	p, err := f.pane(paneID)
+	if err != nil {
+		return err
+	}
+	p.command, p.dead = command, false
+	return nil
+}
+
+func (f *fakeTmux) KillPane(paneID string) error {
+	for i, p := range f.panes {
+		if p.id == paneID {
+			f.panes = append(f.panes[:i], f.panes[i+1:]...)
+			if len(f.panes) == 0 {
+				delete(f.sessions, SessionName)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("can't find pane %s", paneID)
+}
+
+func (f *fakeTmux) SetPaneOption(paneID, option, value string) error {
+	p, err := f.pane(paneID)
+	if err != nil {
+		return err
+	}
+	p.option = value
+	return nil
+}
+
+func (f *fakeTmux) SetWindowOption(target, option, value string) error { return nil }
+func (f *fakeTmux) SelectLayout(target, layout string) error           { return nil }
+
+// shown returns the sessions on the board, or "placeholder" for the
+// placeholder pane
+func (f *fakeTmux) shown() []string {
+	var names []string
+	for _, p := range f.panes {
+		if p.option == "" {
+			names = append(names, "placeholder")
+			continue
+		}
+		if !strings.Contains(p.command, tmux.ShellQuote("="+p.option)) {
+			names = append(names, "mismatch:"+p.option)
+			continue
+		}
+		names = append(names, p.option)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestSync(t *testing.T) {
+	f := newFakeTmux("a", "b", "c")
+
+	steps := []struct {
+		name    string
+		waiting []string
+		want    []string
+	}{
+		{"empty board", nil, []string{"placeholder"}},
+		{"first waiting session reuses the placeholder", []string{"a"}, []string{"a"}},
+		{"more sessions add panes", []string{"a", "b", "c"}, []string{"a", "b", "c"}},
+		{"sessions that stop waiting are closed", []string{"b"}, []string{"b"}},
+		{"unknown tmux sessions are skipped", []string{"b", "gone"}, []string{"b"}},
+		{"last pane turns back into the placeholder", nil, []string{"placeholder"}},
+	}
+	for _, step := range steps {
+		if err := Sync(f, step.waiting); err != nil {
+			t.Fatalf("%s: Sync() error = %v", step.name, err)
+		}
+		if !f.sessions[SessionName] {
+			t.Fatalf("%s: board session was closed", step.name)
+		}
+		if got := f.shown(); !reflect.DeepEqual(got, step.want) {
+			t.Errorf("%s: board shows %v, want %v", step.name, got, step.want)
+		}
+	}
+}
+
+func TestSyncReattachesDeadPanes(t *testing.T) {
+	f := newFakeTmux("a")
+	if err := Sync(f, []string{"a"}); err != nil {
+		t.Fatal(err)
+	}
+	f.panes[0].dead = true
+
+	if err := Sync(f, []string{"a"}); err != nil {
+		t.Fatal(err)
+	}
+	if f.panes[0].dead || f.panes[0].option != "a" {
+		t.Errorf("expected the dead pane to be reattached, got %+v", f.panes[0])
+	}
+}
+
+func TestWaiting(t *testing.T) {
+	dir := t.TempDir()
+	for name, state := range map[string]types.ClaudeState{
+		"b": types.ClaudeStateWaitingForInput,
+		"a": types.ClaudeStateWaitingForInput,
+		"c": types.ClaudeStateRunning,
+	} {
+		if err := status.WriteState(dir, name, state, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := status.WriteAgentState(dir, "c", "agent1", types.ClaudeStateWaitingForInput); err != nil {
+		t.Fatal(err)
+	}
+	// Hooks stopped firing long ago, e.g. Claude was killed while waiting
+	stale := `{"state":"waiting_for_input","updated_at":"2000-01-01T00:00:00Z"}`
+	if err := os.WriteFile(filepath.Join(dir, "d.state"), []byte(stale), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Waiting(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Waiting() = %v, want %v", got, want)
+	}
+
+	if got, err := Waiting(filepath.Join(dir, "missing")); err != nil || len(got) != 0 {
+		t.Errorf("Waiting() on a missing dir = %v, %v", got, err)
+	}
+}
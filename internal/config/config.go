@@ -10,9 +10,25 @@ import (
 	"strings"
 	"time"
 
+	"github.com/mateimicu/tmux-claude-matrix/pkg/client"
 	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
 )
 
+// DefaultBranchPattern is the branch name pattern for new sessions.
+// The {session} placeholder is replaced with the session name.
+const DefaultBranchPattern = "claude/{session}"
+
+// DefaultPreviewLines is the number of Claude pane lines shown in the
+// session picker preview
+const DefaultPreviewLines = 30
+
+// DefaultPreviewWindow is the FZF --preview-window spec of the session picker
+const DefaultPreviewWindow = "right,50%,wrap"
+
+// DefaultNotifyCooldown is the minimum time between two notifications of a
+// session about the same state
+const DefaultNotifyCooldown = time.Minute
+
 // Load reads config from multiple sources (env > files > defaults)
 func Load() (*types.Config, error) {
 	cfg := defaults()
@@ -50,11 +66,21 @@ func defaults() *types.Config {
 		LocalReposFile:     filepath.Join(home, ".tmux-claude-matrix/repos.txt"),
 		WorkspacesEnabled:  true,
 		WorkspacesFile:     filepath.Join(home, ".tmux-claude-matrix/workspaces.yaml"),
+		LayoutsFile:        filepath.Join(home, ".tmux-claude-matrix/layouts.yaml"),
+		UIStateFile:        filepath.Join(home, ".tmux-claude-matrix/ui-state.json"),
+		DaemonSocket:       client.DefaultSocketPath(),
 		ClaudeBin:          findClaudeBin(),
 		ClaudeArgs:         []string{"--dangerously-skip-permissions"},
+		BranchPattern:      DefaultBranchPattern,
 		CacheDir:           filepath.Join(home, ".tmux-claude-matrix/.cache"),
 		CacheTTL:           24 * time.Hour,
 		SessionsDir:        filepath.Join(home, ".tmux-claude-matrix/sessions"),
+		ArchiveDir:         filepath.Join(home, ".tmux-claude-matrix/archives"),
+		PreviewLines:       DefaultPreviewLines,
+		PreviewWindow:      DefaultPreviewWindow,
+		PreviewScrollUp:    "shift-up",
+		PreviewScrollDown:  "shift-down",
+		NotifyCooldown:     DefaultNotifyCooldown,
 	}
 }
 
@@ -150,10 +176,44 @@ func applyConfigValue(cfg *types.Config, key, value string) {
 		}
 	case "SESSIONS_DIR":
 		cfg.SessionsDir = value
+	case "ARCHIVE_DIR":
+		cfg.ArchiveDir = value
+	case "PREVIEW_LINES":
+		if lines, err := strconv.Atoi(value); err == nil && lines >= 0 {
+			cfg.PreviewLines = lines
+		}
+	case "PREVIEW_WINDOW":
+		cfg.PreviewWindow = value
+	case "PREVIEW_SCROLL_UP":
+		cfg.PreviewScrollUp = value
+	case "PREVIEW_SCROLL_DOWN":
+		cfg.PreviewScrollDown = value
+	case "NOTIFY_RULES":
+		cfg.NotifyRules = parseNotifyRules(value)
+	case "NOTIFY_COMMAND":
+		cfg.NotifyCommand = value
+	case "NOTIFY_WEBHOOK_URL":
+		cfg.NotifyWebhookURL = value
+	case "NOTIFY_WEBHOOK_SECRET":
+		cfg.NotifyWebhookKey = value
+	case "NOTIFY_COOLDOWN":
+		if duration, err := time.ParseDuration(value); err == nil && duration >= 0 {
+			cfg.NotifyCooldown = duration
+		}
 	case "WORKSPACES_ENABLED":
 		cfg.WorkspacesEnabled = value == "1" || value == "true"
 	case "WORKSPACES_FILE":
 		cfg.WorkspacesFile = value
+	case "LAYOUTS_FILE":
+		cfg.LayoutsFile = value
+	case "UI_STATE_FILE":
+		cfg.UIStateFile = value
+	case "DAEMON_SOCKET":
+		cfg.DaemonSocket = value
+	case "BRANCH_PATTERN":
+		cfg.BranchPattern = value
+	case "USE_WORKTREES":
+		cfg.UseWorktrees = value == "1" || value == "true"
 	case "DEBUG":
 		cfg.Debug = value == "1" || value == "true"
 	}
@@ -201,17 +261,85 @@ func applyEnvOverrides(cfg *types.Config) {
 	if val := os.Getenv("TMUX_CLAUDE_MATRIX_SESSIONS_DIR"); val != "" {
 		cfg.SessionsDir = val
 	}
+	if val := os.Getenv("TMUX_CLAUDE_MATRIX_ARCHIVE_DIR"); val != "" {
+		cfg.ArchiveDir = val
+	}
+	if val := os.Getenv("TMUX_CLAUDE_MATRIX_PREVIEW_LINES"); val != "" {
+		if lines, err := strconv.Atoi(val); err == nil && lines >= 0 {
+			cfg.PreviewLines = lines
+		}
+	}
+	if val := os.Getenv("TMUX_CLAUDE_MATRIX_PREVIEW_WINDOW"); val != "" {
+		cfg.PreviewWindow = val
+	}
+	if val := os.Getenv("TMUX_CLAUDE_MATRIX_PREVIEW_SCROLL_UP"); val != "" {
+		cfg.PreviewScrollUp = val
+	}
+	if val := os.Getenv("TMUX_CLAUDE_MATRIX_PREVIEW_SCROLL_DOWN"); val != "" {
+		cfg.PreviewScrollDown = val
+	}
+	if val := os.Getenv("TMUX_CLAUDE_MATRIX_NOTIFY_RULES"); val != "" {
+		cfg.NotifyRules = parseNotifyRules(val)
+	}
+	if val := os.Getenv("TMUX_CLAUDE_MATRIX_NOTIFY_COMMAND"); val != "" {
+		cfg.NotifyCommand = val
+	}
+	if val := os.Getenv("TMUX_CLAUDE_MATRIX_NOTIFY_WEBHOOK_URL"); val != "" {
+		cfg.NotifyWebhookURL = val
+	}
+	if val := os.Getenv("TMUX_CLAUDE_MATRIX_NOTIFY_WEBHOOK_SECRET"); val != "" {
+		cfg.NotifyWebhookKey = val
+	}
+	if val := os.Getenv("TMUX_CLAUDE_MATRIX_NOTIFY_COOLDOWN"); val != "" {
+		if duration, err := time.ParseDuration(val); err == nil && duration >= 0 {
+			cfg.NotifyCooldown = duration
+		}
+	}
 	if val := os.Getenv("TMUX_CLAUDE_MATRIX_WORKSPACES_ENABLED"); val != "" {
 		cfg.WorkspacesEnabled = val == "1" || val == "true"
 	}
 	if val := os.Getenv("TMUX_CLAUDE_MATRIX_WORKSPACES_FILE"); val != "" {
 		cfg.WorkspacesFile = val
 	}
+	if val := os.Getenv("TMUX_CLAUDE_MATRIX_LAYOUTS_FILE"); val != "" {
+		cfg.LayoutsFile = val
+	}
+	if val := os.Getenv("TMUX_CLAUDE_MATRIX_UI_STATE_FILE"); val != "" {
+		cfg.UIStateFile = val
+	}
+	if val := os.Getenv("TMUX_CLAUDE_MATRIX_DAEMON_SOCKET"); val != "" {
+		cfg.DaemonSocket = val
+	}
+	if val := os.Getenv("TMUX_CLAUDE_MATRIX_BRANCH_PATTERN"); val != "" {
+		cfg.BranchPattern = val
+	}
+	if val := os.Getenv("TMUX_CLAUDE_MATRIX_USE_WORKTREES"); val != "" {
+		cfg.UseWorktrees = val == "1" || val == "true"
+	}
 	if val := os.Getenv("TMUX_CLAUDE_MATRIX_DEBUG"); val != "" {
 		cfg.Debug = val == "1" || val == "true"
 	}
 }
 
+// parseNotifyRules parses space-separated state:backend,backend rules, e.g.
+// "waiting_for_input:tmux,bell error:tmux,notify-send". Rules without
+// backends are dropped; names are checked when the notifier is built.
+func parseNotifyRules(value string) map[string][]string {
+	rules := make(map[string][]string)
+	for _, rule := range strings.Fields(value) {
+		state, backends, ok := strings.Cut(rule, ":")
+		if !ok || state == "" {
+			continue
+		}
+		for _, backend := range strings.Split(backends, ",") {
+			if backend = strings.TrimSpace(backend); backend != "" {
+				rules[state] = append(rules[state], backend)
+			}
+		}
+	}
+	return rules
+}
+
 func validate(cfg *types.Config) error {
 	if cfg.CloneDir == "" {
 		return fmt.Errorf("clone directory cannot be empty")
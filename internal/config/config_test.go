@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -90,3 +91,58 @@ func TestLoadDebugConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadPreviewConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		configLine string
+		envVal     string
+		wantLines  int
+	}{
+		{name: "default", wantLines: DefaultPreviewLines},
+		{name: "config file", configLine: "PREVIEW_LINES=80", wantLines: 80},
+		{name: "zero disables the pane", configLine: "PREVIEW_LINES=0", wantLines: 0},
+		{name: "invalid value is ignored", configLine: "PREVIEW_LINES=-3", wantLines: DefaultPreviewLines},
+		{name: "env var overrides config file", configLine: "PREVIEW_LINES=80", envVal: "10", wantLines: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			configDir := filepath.Join(tmpDir, ".config", "tmux-claude-matrix")
+			if err := os.MkdirAll(configDir, 0755); err != nil {
+				t.Fatal(err)
+			}
+			t.Setenv("HOME", tmpDir)
+			t.Setenv("TMUX_CLAUDE_MATRIX_PREVIEW_LINES", tt.envVal)
+
+			if tt.configLine != "" {
+				if err := os.WriteFile(filepath.Join(configDir, "config"), []byte(tt.configLine+"\n"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			cfg, err := Load()
+			if err != nil {
+				t.Fatalf("Load() error: %v", err)
+			}
+			if cfg.PreviewLines != tt.wantLines {
+				t.Errorf("cfg.PreviewLines = %d, want %d", cfg.PreviewLines, tt.wantLines)
+			}
+			if cfg.PreviewWindow != DefaultPreviewWindow {
+				t.Errorf("cfg.PreviewWindow = %q, want %q", cfg.PreviewWindow, DefaultPreviewWindow)
+			}
+		})
+	}
+}
+
+func TestParseNotifyRules(t *testing.T) {
+	got := parseNotifyRules("waiting_for_input:tmux,bell  error:command, idle: :tmux")
+	want := map[string][]string{
+		"waiting_for_input": {"tmux", "bell"},
+		"error":             {"command"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseNotifyRules() = %v, want %v", got, want)
+	}
+}
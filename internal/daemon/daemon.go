@@ -0,0 +1,448 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mateimicu/tmux-claude-matrix/internal/logging"
+	"github.com/mateimicu/tmux-claude-matrix/internal/session"
+	"github.com/mateimicu/tmux-claude-matrix/internal/status"
+	"github.com/mateimicu/tmux-claude-matrix/pkg/client"
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+const (
+	// DefaultInterval is how often the status dir and tmux sessions are
+	// rescanned
+	DefaultInterval = time.Second
+	// DefaultProbeInterval is how often the Claude processes of a session
+	// are checked, which costs several tmux, pgrep and ps calls
+	DefaultProbeInterval = 15 * time.Second
+	// goneAfterMisses is how many probes in a row must find no Claude before
+	// a session's state is cleared, so one racy process listing can't wipe it
+	goneAfterMisses = 2
+)
+
+// Prober is the subset of tmux operations the daemon needs
+type Prober interface {
+	ListSessions() ([]string, error)
+	LookupClaudePane(session string) (string, bool, error)
+	GetClaudeStatus(session string) bool
+	GetDetailedClaudeState(session string) (types.ClaudeState, time.Time)
+}
+
+// Options configures a Daemon
+type Options struct {
+	StatusDir     string
+	Interval      time.Duration
+	ProbeInterval time.Duration
+}
+
+// probe is the result of the process checks of a live session
+type probe struct {
+	at           time.Time
+	hookState    bool              // The session had an aggregate state file
+	err          error             // The pane lookup failed, so alive is unknown
+	alive        bool              // Claude runs in some pane of the session
+	misses       int               // Consecutive successful probes that found no Claude
+	running      bool              // Claude runs in the session's current window
+	state        types.ClaudeState // Process-based state, used without a hook state
+	lastActivity time.Time
+}
+
+// Daemon keeps an in-memory index of the status of every managed session,
+// refreshed from the status dir and tmux, and serves it over a Unix socket.
+// Subscribers are sent an event for every state change the refreshes see.
+// While refreshing it removes the state files of sessions whose Claude
+// process is gone and expires stale agent files.
+type Daemon struct {
+	sessionMgr *session.Manager
+	prober     Prober
+	log        *logging.Logger
+	opts       Options
+
+	probes map[string]probe // Only touched by the refresh loop
+
+	mu        sync.RWMutex
+	sessions  []*types.SessionStatus
+	unmanaged []*types.SessionStatus
+
+	subsMu sync.Mutex
+	subs   map[chan *client.Event]struct{}
+}
+
+// New returns a daemon indexing the sessions of sessionMgr
+func New(sessionMgr *session.Manager, prober Prober, log *logging.Logger, opts Options) *Daemon {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	if opts.ProbeInterval <= 0 {
+		opts.ProbeInterval = DefaultProbeInterval
+	}
+	return &Daemon{
+		sessionMgr: sessionMgr,
+		prober:     prober,
+		log:        log,
+		opts:       opts,
+		probes:     make(map[string]probe),
+		subs:       make(map[chan *client.Event]struct{}),
+	}
+}
+
+// Run serves the index on socketPath until ctx is cancelled. It fails if
+// another daemon is already listening there.
+func (d *Daemon) Run(ctx context.Context, socketPath string) error {
+	ln, err := Listen(socketPath)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(socketPath) //nolint:errcheck // Best-effort cleanup on shutdown
+
+	// Build the index before accepting so the first client gets real data
+	if err := d.Refresh(time.Now()); err != nil {
+		d.log.Warnf("⚠️  Refresh failed: %v\n", err)
+	}
+
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- Serve(ln, d) }()
+
+	ticker := time.NewTicker(d.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			ln.Close() //nolint:errcheck // Unblocks Serve
+			<-serveErr
+			return nil
+		case err := <-serveErr:
+			return fmt.Errorf("socket server stopped: %w", err)
+		case now := <-ticker.C:
+			if err := d.Refresh(now); err != nil {
+				d.log.Warnf("⚠️  Refresh failed: %v\n", err)
+			}
+		}
+	}
+}
+
+// Sessions returns the current index. The statuses must not be modified.
+func (d *Daemon) Sessions() []*types.SessionStatus {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.sessions
+}
+
+// Refresh rebuilds the index. Sessions whose last probe is older than the
+// probe interval are probed again and reconciled with their state files.
+func (d *Daemon) Refresh(now time.Time) error {
+	sessions, err := d.sessionMgr.List()
+	var corrupt *session.CorruptError
+	if errors.As(err, &corrupt) {
+		d.log.Debugf("Skipping unreadable session metadata: %v\n", corrupt)
+	} else if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	liveSessions, err := d.prober.ListSessions()
+	if err != nil {
+		return fmt.Errorf("failed to list tmux sessions: %w", err)
+	}
+	live := make(map[string]bool, len(liveSessions))
+	for _, name := range liveSessions {
+		live[name] = true
+	}
+
+	states, err := status.ListStates(d.opts.StatusDir)
+	if err != nil {
+		return fmt.Errorf("failed to read session states: %w", err)
+	}
+
+	index := make([]*types.SessionStatus, 0, len(sessions))
+	seen := make(map[string]bool, len(sessions))
+	for _, sess := range sessions {
+		seen[sess.Name] = true
+		sf := states[sess.Name]
+
+		if !live[sess.Name] {
+			delete(d.probes, sess.Name)
+			if sf != nil {
+				// The tmux session is gone, and Claude with it
+				d.clearState(sess.Name)
+			}
+			index = append(index, statusFor(sess, false, nil, probe{}))
+			continue
+		}
+
+		// A state file appearing or disappearing means Claude started or
+		// ended, which invalidates the last probe
+		p, ok := d.probes[sess.Name]
+		if !ok || now.Sub(p.at) >= d.opts.ProbeInterval || p.hookState != (sf != nil) {
+			misses := p.misses
+			p = d.probe(sess.Name, now)
+			if p.err == nil && !p.alive {
+				p.misses = misses + 1
+			}
+			sf = d.reconcile(sess.Name, sf, p)
+			p.hookState = sf != nil
+			d.probes[sess.Name] = p
+		}
+		index = append(index, statusFor(sess, true, sf, p))
+	}
+
+	for name := range d.probes {
+		if !seen[name] {
+			delete(d.probes, name)
+		}
+	}
+
+	// Claude also reports the state of tmux sessions created by hand
+	var unmanaged []*types.SessionStatus
+	for name, sf := range states {
+		if seen[name] || !live[name] {
+			continue
+		}
+		unmanaged = append(unmanaged, &types.SessionStatus{
+			Session:        &types.Session{Name: name},
+			TmuxActive:     true,
+			ClaudeState:    types.ClaudeState(sf.State),
+			LastActivity:   sf.UpdatedAt,
+			AggregateState: types.ClaudeState(sf.State),
+			StateSince:     sf.Since,
+		})
+	}
+	sort.Slice(unmanaged, func(i, j int) bool { return unmanaged[i].Session.Name < unmanaged[j].Session.Name })
+
+	d.mu.Lock()
+	previous := d.sessions
+	d.sessions = index
+	d.unmanaged = unmanaged
+	d.mu.Unlock()
+
+	d.publish(diffStatuses(previous, index, now))
+	return nil
+}
+
+// Unmanaged returns the live tmux sessions without metadata that have an
+// aggregate state file. The statuses must not be modified.
+func (d *Daemon) Unmanaged() []*types.SessionStatus {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.unmanaged
+}
+
+// Session returns the indexed status of the named session, or nil
+func (d *Daemon) Session(name string) *types.SessionStatus {
+	for _, st := range d.Sessions() {
+		if st.Session.Name == name {
+			return st
+		}
+	}
+	return nil
+}
+
+// subscriberBuffer is how many events a subscriber may fall behind before
+// it is dropped
+const subscriberBuffer = 64
+
+// subscribe registers a subscriber. The channel is closed when the
+// subscriber is dropped for falling behind.
+func (d *Daemon) subscribe() chan *client.Event {
+	ch := make(chan *client.Event, subscriberBuffer)
+	d.subsMu.Lock()
+	d.subs[ch] = struct{}{}
+	d.subsMu.Unlock()
+	return ch
+}
+
+// unsubscribe removes a subscriber that went away
+func (d *Daemon) unsubscribe(ch chan *client.Event) {
+	d.subsMu.Lock()
+	defer d.subsMu.Unlock()
+	if _, ok := d.subs[ch]; ok {
+		delete(d.subs, ch)
+		close(ch)
+	}
+}
+
+// publish sends events to every subscriber without blocking the refresh
+// loop on a slow one
+func (d *Daemon) publish(events []*client.Event) {
+	if len(events) == 0 {
+		return
+	}
+
+	d.subsMu.Lock()
+	defer d.subsMu.Unlock()
+	for ch := range d.subs {
+		if !sendAll(ch, events) {
+			delete(d.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// sendAll queues events on ch, reporting false if its buffer is full
+func sendAll(ch chan *client.Event, events []*client.Event) bool {
+	for _, ev := range events {
+		select {
+		case ch <- ev:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// diffStatuses returns an event for every session whose Claude state
+// differs between two indexes, including sessions added or removed
+func diffStatuses(previous, current []*types.SessionStatus, now time.Time) []*client.Event {
+	before := make(map[string]types.ClaudeState, len(previous))
+	for _, st := range previous {
+		before[st.Session.Name] = st.ClaudeState
+	}
+
+	var events []*client.Event
+	for _, st := range current {
+		name := st.Session.Name
+		old, existed := before[name]
+		delete(before, name)
+		if existed && old == st.ClaudeState {
+			continue
+		}
+		events = append(events, &client.Event{
+			Session:   name,
+			OldState:  old,
+			NewState:  st.ClaudeState,
+			Status:    st,
+			Timestamp: now,
+		})
+	}
+
+	// What is left was removed; report in a stable order
+	removed := make([]string, 0, len(before))
+	for name := range before {
+		removed = append(removed, name)
+	}
+	sort.Strings(removed)
+	for _, name := range removed {
+		events = append(events, &client.Event{Session: name, OldState: before[name], Timestamp: now})
+	}
+	return events
+}
+
+// probe runs the process checks of a live session
+func (d *Daemon) probe(name string, now time.Time) probe {
+	_, alive, err := d.prober.LookupClaudePane(name)
+	if err != nil {
+		d.log.Debugf("Probing %s failed: %v\n", name, err)
+	}
+	state, lastActivity := d.prober.GetDetailedClaudeState(name)
+	return probe{
+		at:           now,
+		err:          err,
+		alive:        alive,
+		running:      d.prober.GetClaudeStatus(name),
+		state:        state,
+		lastActivity: lastActivity,
+	}
+}
+
+// reconcile brings the state files of a freshly probed session in line
+// with its processes and returns its current aggregate state file, if any
+func (d *Daemon) reconcile(name string, sf *status.StateFile, p probe) *status.StateFile {
+	if sf == nil {
+		return nil
+	}
+
+	if p.err == nil && !p.alive {
+		if p.misses < goneAfterMisses {
+			d.log.Debugf("No Claude found in %s, checking again before clearing its state\n", name)
+			return sf
+		}
+		// Claude exited without its SessionEnd hook firing, e.g. killed
+		d.log.Debugf("Claude is gone from %s, clearing its %s state\n", name, sf.State)
+		d.clearState(name)
+		return nil
+	}
+
+	expired, err := status.ExpireAgentStates(d.opts.StatusDir, name, status.DefaultStaleThreshold)
+	if err != nil {
+		d.log.Warnf("⚠️  Failed to expire agent states of %s: %v\n", name, err)
+		return sf
+	}
+	if !expired {
+		return sf
+	}
+	sf, err = status.ReadState(d.opts.StatusDir, name)
+	if err != nil {
+		return nil
+	}
+	return sf
+}
+
+// clearState removes every state file of a session whose Claude is gone
+func (d *Daemon) clearState(name string) {
+	if err := status.RemoveAllAgentStates(d.opts.StatusDir, name); err != nil {
+		d.log.Warnf("⚠️  Failed to remove agent states of %s: %v\n", name, err)
+	}
+	if err := status.RemoveState(d.opts.StatusDir, name); err != nil {
+		d.log.Warnf("⚠️  Failed to remove state of %s: %v\n", name, err)
+	}
+}
+
+// statusFor builds the status of a session the same way the list view does:
+// a fresh hook state wins over the process-based state of the last probe
+func statusFor(sess *types.Session, tmuxActive bool, sf *status.StateFile, p probe) *types.SessionStatus {
+	st := &types.SessionStatus{
+		Session:     sess,
+		TmuxActive:  tmuxActive,
+		ClaudeState: types.ClaudeStateStopped,
+	}
+	if !tmuxActive {
+		return st
+	}
+
+	st.ClaudeRunning = p.running
+	st.ClaudeState, st.LastActivity = p.state, p.lastActivity
+	if sf != nil {
+		st.AggregateState, st.StateSince = types.ClaudeState(sf.State), sf.Since
+	}
+	if sf != nil && !status.IsStale(sf, status.DefaultStaleThreshold) {
+		st.ClaudeState, st.LastActivity = types.ClaudeState(sf.State), sf.UpdatedAt
+	}
+	if st.ClaudeState == "" {
+		st.ClaudeState = types.ClaudeStateUnknown
+	}
+	return st
+}
+
+// Listen listens on the Unix socket at socketPath, replacing a socket left
+// behind by a daemon that died. Only the owner can connect.
+func Listen(socketPath string) (net.Listener, error) {
+	if conn, err := net.DialTimeout("unix", socketPath, client.DialTimeout); err == nil {
+		conn.Close() //nolint:errcheck // Only probing for a live daemon
+		return nil, fmt.Errorf("a daemon is already listening on %s", socketPath)
+	}
+	if err := os.MkdirAll(filepath.Dir(socketPath), 0o755); err != nil {
+		return nil, err
+	}
+	if err := os.Remove(socketPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+	}
+
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(socketPath, 0o600); err != nil {
+		ln.Close() //nolint:errcheck // Already failing
+		return nil, err
+	}
+	return ln, nil
+}
@@ -0,0 +1,294 @@
+package daemon
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mateimicu/tmux-claude-matrix/internal/logging"
+	"github.com/mateimicu/tmux-claude-matrix/internal/session"
+	"github.com/mateimicu/tmux-claude-matrix/internal/status"
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+type fakeProber struct {
+	live   []string
+	claude map[string]bool // Sessions Claude runs in
+	err    error           // Returned by every pane lookup
+	probes int
+}
+
+func (f *fakeProber) ListSessions() ([]string, error) { return f.live, nil }
+
+func (f *fakeProber) LookupClaudePane(session string) (string, bool, error) {
+	f.probes++
+	if f.err != nil {
+		return "", false, f.err
+	}
+	return "%1", f.claude[session], nil
+}
+
+func (f *fakeProber) GetClaudeStatus(session string) bool { return f.claude[session] }
+
+func (f *fakeProber) GetDetailedClaudeState(session string) (types.ClaudeState, time.Time) {
+	if f.claude[session] {
+		return types.ClaudeStateUnknown, time.Time{}
+	}
+	return types.ClaudeStateStopped, time.Time{}
+}
+
+// newTestDaemon returns a daemon over fresh metadata and status dirs holding
+// the given sessions
+func newTestDaemon(t *testing.T, prober Prober, names ...string) (*Daemon, string) {
+	t.Helper()
+	sessionMgr := session.NewManager(t.TempDir())
+	for _, name := range names {
+		if err := sessionMgr.Save(&types.Session{Name: name, CreatedAt: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	statusDir := t.TempDir()
+	return New(sessionMgr, prober, logging.New(false), Options{StatusDir: statusDir}), statusDir
+}
+
+func statesByName(d *Daemon) map[string]types.ClaudeState {
+	states := make(map[string]types.ClaudeState)
+	for _, st := range d.Sessions() {
+		states[st.Session.Name] = st.ClaudeState
+	}
+	return states
+}
+
+func TestRefresh(t *testing.T) {
+	prober := &fakeProber{
+		live:   []string{"working", "crashed", "plain"},
+		claude: map[string]bool{"working": true, "plain": true},
+	}
+	d, statusDir := newTestDaemon(t, prober, "working", "crashed", "plain", "closed")
+
+	for name, state := range map[string]types.ClaudeState{
+		"working": types.ClaudeStateWaitingForInput,
+		"crashed": types.ClaudeStateRunning,
+		"closed":  types.ClaudeStateIdle,
+	} {
+		if err := status.WriteAgentState(statusDir, name, "agent", state); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := status.UpdateAggregate(statusDir, name, status.DefaultStaleThreshold); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now := time.Now()
+	if err := d.Refresh(now); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	// One probe finding no Claude isn't enough to clear a state
+	if got := statesByName(d)["crashed"]; got != types.ClaudeStateRunning {
+		t.Errorf("crashed: state = %q after one probe, want %q", got, types.ClaudeStateRunning)
+	}
+	if err := d.Refresh(now.Add(DefaultProbeInterval)); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	want := map[string]types.ClaudeState{
+		"working": types.ClaudeStateWaitingForInput, // Hook state wins
+		"crashed": types.ClaudeStateStopped,         // Claude is gone
+		"plain":   types.ClaudeStateUnknown,         // Process-based state without hooks
+		"closed":  types.ClaudeStateStopped,         // tmux session is gone
+	}
+	got := statesByName(d)
+	for name, state := range want {
+		if got[name] != state {
+			t.Errorf("%s: state = %q, want %q", name, got[name], state)
+		}
+	}
+
+	// The state files of sessions without Claude are cleared
+	states, err := status.ListStates(statusDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 1 || states["working"] == nil {
+		t.Errorf("expected only the working session to keep its state, got %v", states)
+	}
+	for _, name := range []string{"crashed", "closed"} {
+		matches, _ := filepath.Glob(filepath.Join(statusDir, name+".agent.*.state"))
+		if len(matches) != 0 {
+			t.Errorf("%s: expected agent files to be removed, got %v", name, matches)
+		}
+	}
+}
+
+func TestRefreshReportsAggregateStates(t *testing.T) {
+	prober := &fakeProber{
+		live:   []string{"s", "scratch"},
+		claude: map[string]bool{"s": true, "scratch": true},
+	}
+	d, statusDir := newTestDaemon(t, prober, "s", "closed")
+
+	// scratch is a tmux session created by hand, gone one was closed
+	for _, name := range []string{"s", "scratch", "gone"} {
+		if err := status.WriteAgentState(statusDir, name, "agent", types.ClaudeStateWaitingForInput); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := status.UpdateAggregate(statusDir, name, status.DefaultStaleThreshold); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := d.Refresh(time.Now()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	st := d.Session("s")
+	if st.AggregateState != types.ClaudeStateWaitingForInput || st.StateSince.IsZero() {
+		t.Errorf("s: aggregate state = %q since %v, want %q with a start", st.AggregateState, st.StateSince, types.ClaudeStateWaitingForInput)
+	}
+	if st := d.Session("closed"); st.AggregateState != "" {
+		t.Errorf("closed: aggregate state = %q, want none", st.AggregateState)
+	}
+
+	unmanaged := d.Unmanaged()
+	if len(unmanaged) != 1 || unmanaged[0].Session.Name != "scratch" {
+		t.Fatalf("Unmanaged() = %v, want only scratch", unmanaged)
+	}
+	if got := unmanaged[0].AggregateState; got != types.ClaudeStateWaitingForInput {
+		t.Errorf("scratch: aggregate state = %q, want %q", got, types.ClaudeStateWaitingForInput)
+	}
+}
+
+func TestRefreshKeepsStateWhenProbeFails(t *testing.T) {
+	prober := &fakeProber{live: []string{"s"}, err: errors.New("tmux: server exited")}
+	d, statusDir := newTestDaemon(t, prober, "s")
+
+	if err := status.WriteAgentState(statusDir, "s", "agent", types.ClaudeStateWaitingForInput); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := status.UpdateAggregate(statusDir, "s", status.DefaultStaleThreshold); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := d.Refresh(now.Add(time.Duration(i) * DefaultProbeInterval)); err != nil {
+			t.Fatalf("Refresh failed: %v", err)
+		}
+	}
+	if prober.probes != 3 {
+		t.Fatalf("expected 3 probes, got %d", prober.probes)
+	}
+	if _, err := status.ReadState(statusDir, "s"); err != nil {
+		t.Errorf("expected the state to survive failed probes, got %v", err)
+	}
+}
+
+func TestRefreshExpiresStaleAgents(t *testing.T) {
+	prober := &fakeProber{live: []string{"s"}, claude: map[string]bool{"s": true}}
+	d, statusDir := newTestDaemon(t, prober, "s")
+
+	if err := status.WriteAgentState(statusDir, "s", "fresh", types.ClaudeStateIdle); err != nil {
+		t.Fatal(err)
+	}
+	// An agent whose hooks stopped firing long ago, e.g. a killed subagent
+	stalePath := filepath.Join(statusDir, "s.agent.stale.state")
+	if err := os.WriteFile(stalePath, []byte(`{"state":"waiting_for_input","updated_at":"2000-01-01T00:00:00Z"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := status.WriteState(statusDir, "s", types.ClaudeStateWaitingForInput, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := d.Refresh(time.Now()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	if got := statesByName(d)["s"]; got != types.ClaudeStateIdle {
+		t.Errorf("state = %q, want %q once the stale agent expired", got, types.ClaudeStateIdle)
+	}
+	if _, err := os.Stat(stalePath); !os.IsNotExist(err) {
+		t.Error("expected stale agent file to be removed")
+	}
+}
+
+func TestRefreshProbeInterval(t *testing.T) {
+	prober := &fakeProber{live: []string{"s"}, claude: map[string]bool{"s": true}}
+	d, statusDir := newTestDaemon(t, prober, "s")
+
+	now := time.Now()
+	refresh := func(at time.Time) {
+		t.Helper()
+		if err := d.Refresh(at); err != nil {
+			t.Fatalf("Refresh failed: %v", err)
+		}
+	}
+
+	refresh(now)
+	refresh(now.Add(time.Second))
+	if prober.probes != 1 {
+		t.Fatalf("expected one probe within the probe interval, got %d", prober.probes)
+	}
+
+	// Claude starting a conversation invalidates the last probe
+	if err := status.WriteAgentState(statusDir, "s", "agent", types.ClaudeStateRunning); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := status.UpdateAggregate(statusDir, "s", status.DefaultStaleThreshold); err != nil {
+		t.Fatal(err)
+	}
+	refresh(now.Add(2 * time.Second))
+	if prober.probes != 2 {
+		t.Errorf("expected a new state file to trigger a probe, got %d probes", prober.probes)
+	}
+	if got := statesByName(d)["s"]; got != types.ClaudeStateRunning {
+		t.Errorf("state = %q, want %q", got, types.ClaudeStateRunning)
+	}
+
+	refresh(now.Add(3 * time.Second))
+	refresh(now.Add(2*time.Second + DefaultProbeInterval))
+	if prober.probes != 3 {
+		t.Errorf("expected a probe after the probe interval, got %d probes", prober.probes)
+	}
+}
+
+func TestDiffStatuses(t *testing.T) {
+	st := func(name string, state types.ClaudeState) *types.SessionStatus {
+		return &types.SessionStatus{Session: &types.Session{Name: name}, ClaudeState: state}
+	}
+	previous := []*types.SessionStatus{
+		st("same", types.ClaudeStateRunning),
+		st("changed", types.ClaudeStateRunning),
+		st("deleted", types.ClaudeStateIdle),
+	}
+	current := []*types.SessionStatus{
+		st("same", types.ClaudeStateRunning),
+		st("changed", types.ClaudeStateWaitingForInput),
+		st("created", types.ClaudeStateStopped),
+	}
+
+	events := diffStatuses(previous, current, time.Now())
+
+	type change struct{ old, new types.ClaudeState }
+	got := make(map[string]change)
+	for _, ev := range events {
+		got[ev.Session] = change{ev.OldState, ev.NewState}
+		if (ev.Status == nil) != (ev.NewState == "") {
+			t.Errorf("%s: status %v does not match new state %q", ev.Session, ev.Status, ev.NewState)
+		}
+	}
+	want := map[string]change{
+		"changed": {types.ClaudeStateRunning, types.ClaudeStateWaitingForInput},
+		"created": {"", types.ClaudeStateStopped},
+		"deleted": {types.ClaudeStateIdle, ""},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got events %v, want %v", got, want)
+	}
+	for name, c := range want {
+		if got[name] != c {
+			t.Errorf("%s: got %v, want %v", name, got[name], c)
+		}
+	}
+}
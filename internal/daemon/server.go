@@ -0,0 +1,98 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/mateimicu/tmux-claude-matrix/pkg/client"
+)
+
+// connTimeout bounds a request/response exchange and each event write
+const connTimeout = 2 * time.Second
+
+// Serve answers clients on ln until it is closed. The protocol is described
+// in package client.
+func Serve(ln net.Listener, d *Daemon) error {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go handleConn(conn, d)
+	}
+}
+
+func handleConn(conn net.Conn, d *Daemon) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(connTimeout)) //nolint:errcheck // Worst case the client waits for its own timeout
+
+	reader := bufio.NewReader(conn)
+	var req client.Request
+	line, err := reader.ReadBytes('\n')
+	if err == nil {
+		err = json.Unmarshal(line, &req)
+	}
+
+	enc := json.NewEncoder(conn)
+	var resp client.Response
+	switch {
+	case err != nil:
+		resp.Error = fmt.Sprintf("bad request: %v", err)
+	case req.Method == client.MethodList:
+		resp.Sessions = d.Sessions()
+		resp.Unmanaged = d.Unmanaged()
+	case req.Method == client.MethodGet:
+		resp.Session = d.Session(req.Session)
+	case req.Method == client.MethodSubscribe:
+		serveSubscription(conn, reader, enc, d)
+		return
+	default:
+		resp.Error = fmt.Sprintf("unknown method %q", req.Method)
+	}
+
+	enc.Encode(&resp) //nolint:errcheck // The client is gone
+}
+
+// serveSubscription acknowledges a subscribe request and streams events
+// until the client hangs up or falls too far behind
+func serveSubscription(conn net.Conn, reader *bufio.Reader, enc *json.Encoder, d *Daemon) {
+	events := d.subscribe()
+	defer d.unsubscribe(events)
+
+	if err := enc.Encode(&client.Response{}); err != nil {
+		return
+	}
+
+	// Subscribers send nothing after the request, so a read returning
+	// means the client closed the connection
+	gone := make(chan struct{})
+	go func() {
+		conn.SetReadDeadline(time.Time{}) //nolint:errcheck // Without it the read times out and ends the stream early
+		io.Copy(io.Discard, reader)       //nolint:errcheck // Any error means the client is gone
+		close(gone)
+	}()
+
+	for {
+		select {
+		case <-gone:
+			return
+		case ev, ok := <-events:
+			if !ok {
+				// Dropped for falling behind; the client sees the stream end
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(connTimeout)) //nolint:errcheck // A stuck write then blocks only this subscriber
+			if err := enc.Encode(ev); err != nil {
+				return
+			}
+		}
+	}
+}
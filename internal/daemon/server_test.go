@@ -0,0 +1,156 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mateimicu/tmux-claude-matrix/internal/status"
+	"github.com/mateimicu/tmux-claude-matrix/pkg/client"
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+// serveTestDaemon serves d on a fresh socket and returns a client for it
+func serveTestDaemon(t *testing.T, d *Daemon) *client.Client {
+	t.Helper()
+	socketPath := filepath.Join(t.TempDir(), "daemon.sock")
+	ln, err := Listen(socketPath)
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	go Serve(ln, d) //nolint:errcheck // Stops when the listener closes
+	t.Cleanup(func() { ln.Close() })
+	return client.New(socketPath)
+}
+
+func TestServeListAndGet(t *testing.T) {
+	prober := &fakeProber{live: []string{"a"}}
+	d, _ := newTestDaemon(t, prober, "a", "b")
+	if err := d.Refresh(time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	c := serveTestDaemon(t, d)
+	ctx := context.Background()
+
+	if _, err := Listen(c.SocketPath); err == nil {
+		t.Error("expected a second daemon on the same socket to fail")
+	}
+
+	sessions, err := c.List(ctx)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("got %d sessions, want 2", len(sessions))
+	}
+	active := make(map[string]bool)
+	for _, st := range sessions {
+		active[st.Session.Name] = st.TmuxActive
+	}
+	if !active["a"] || active["b"] {
+		t.Errorf("unexpected tmux activity %v", active)
+	}
+
+	st, err := c.Get(ctx, "a")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if st.Session.Name != "a" || !st.TmuxActive {
+		t.Errorf("Get(a) = %+v", st)
+	}
+
+	if _, err := c.Get(ctx, "missing"); !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("Get(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestServeSubscribe(t *testing.T) {
+	prober := &fakeProber{live: []string{"s"}, claude: map[string]bool{"s": true}}
+	d, statusDir := newTestDaemon(t, prober, "s")
+	if err := d.Refresh(time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	c := serveTestDaemon(t, d)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan *client.Event, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Subscribe(ctx, func(ev *client.Event) { events <- ev })
+	}()
+
+	// Wait for the subscription to be registered before changing state
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		d.subsMu.Lock()
+		n := len(d.subs)
+		d.subsMu.Unlock()
+		if n > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("subscription never registered")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if err := status.WriteAgentState(statusDir, "s", "agent", types.ClaudeStateWaitingForInput); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := status.UpdateAggregate(statusDir, "s", status.DefaultStaleThreshold); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Refresh(time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case ev := <-events:
+		if ev.Session != "s" || ev.OldState != types.ClaudeStateUnknown || ev.NewState != types.ClaudeStateWaitingForInput {
+			t.Errorf("unexpected event %+v", ev)
+		}
+		if ev.Status == nil || ev.Status.ClaudeState != types.ClaudeStateWaitingForInput {
+			t.Errorf("event status = %+v", ev.Status)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event received")
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Subscribe returned %v after cancellation, want nil", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Subscribe did not return after cancellation")
+	}
+}
+
+func TestSlowSubscriberDropped(t *testing.T) {
+	d, _ := newTestDaemon(t, &fakeProber{})
+	ch := d.subscribe()
+
+	events := make([]*client.Event, subscriberBuffer+1)
+	for i := range events {
+		events[i] = &client.Event{Session: "s"}
+	}
+	d.publish(events)
+
+	for range ch {
+		// Drains the buffer; the loop ends once the channel is closed
+	}
+	d.subsMu.Lock()
+	remaining := len(d.subs)
+	d.subsMu.Unlock()
+	if remaining != 0 {
+		t.Error("expected the subscriber that fell behind to be dropped")
+	}
+
+	// The connection handler still unsubscribes; that must not panic
+	d.unsubscribe(ch)
+}
@@ -0,0 +1,209 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mateimicu/tmux-claude-matrix/internal/fzf"
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+// Row is one session on the dashboard
+type Row struct {
+	Status   *types.SessionStatus
+	Since    time.Time // When the session entered its Claude state; zero when unknown
+	Git      string    // Short summary of local changes; empty when unknown
+	LastLine string    // Last non-blank line of the Claude pane
+}
+
+// Input is a key press the dashboard understands
+type Input int
+
+const (
+	InputNone Input = iota
+	InputUp
+	InputDown
+	InputQuit
+	InputRefresh
+	InputSwitch
+	InputDelete
+	InputRename
+	InputTag
+	InputNotes
+	InputTools
+)
+
+// ParseInput maps the bytes of one key press to an Input. The bindings
+// match the session list where it has one.
+func ParseInput(b []byte) Input {
+	switch string(b) {
+	case "\x1b[A", "\x1bOA", "k", "\x10": // Up, ctrl-p
+		return InputUp
+	case "\x1b[B", "\x1bOB", "j", "\x0e": // Down, ctrl-n
+		return InputDown
+	case "q", "\x1b", "\x03": // Esc, ctrl-c
+		return InputQuit
+	case "g", "\x0c": // ctrl-l
+		return InputRefresh
+	case "\r", "\n":
+		return InputSwitch
+	case "\x04": // ctrl-d
+		return InputDelete
+	case "\x12": // ctrl-r
+		return InputRename
+	case "\x1bt": // alt-t
+		return InputTag
+	case "\x05": // ctrl-e
+		return InputNotes
+	case "\x13": // ctrl-s
+		return InputTools
+	default:
+		return InputNone
+	}
+}
+
+// legend lists the key bindings in the footer
+const legend = "↑↓ navigate | enter: switch | ctrl-d: delete | ctrl-r: rename | alt-t: tags | ctrl-e: notes | ctrl-s: tools | q: quit"
+
+// column is a fixed-width column of the session table
+type column struct {
+	title string
+	width int
+	value func(r *Row, now time.Time) string
+}
+
+var columns = []column{
+	{"CLAUDE", 10, func(r *Row, _ time.Time) string {
+		return fzf.ClaudeStatusIndicator(r.Status.ClaudeState) + " " + fzf.ClaudeStateLabel(r.Status.ClaudeState)
+	}},
+	{"FOR", 5, func(r *Row, now time.Time) string { return timeInState(r, now) }},
+	{"TITLE", 28, func(r *Row, _ time.Time) string {
+		if r.Status.Session.Title != "" {
+			return r.Status.Session.Title
+		}
+		return r.Status.Session.Name
+	}},
+	{"BRANCH", 24, func(r *Row, _ time.Time) string { return orDash(r.Status.Session.Branch) }},
+	{"GIT", 12, func(r *Row, _ time.Time) string { return orDash(r.Git) }},
+}
+
+// Render draws the dashboard for a terminal of width x height cells. The
+// selected row is highlighted and kept in view; the last pane line of each
+// session fills the remaining width.
+func Render(rows []*Row, selected, width, height int, now time.Time, message string) string {
+	var b strings.Builder
+
+	header := fmt.Sprintf("claude-matrix dashboard | %d sessions | %s", len(rows), now.Format("15:04:05"))
+	b.WriteString("\x1b[1m" + fit(header, width) + "\x1b[0m\r\n")
+
+	// Each row starts with a 2-cell cursor marker
+	fixed := 2
+	var head strings.Builder
+	head.WriteString("  ")
+	for _, c := range columns {
+		head.WriteString(fzf.PadToDisplayWidth(c.title, c.width) + " ")
+		fixed += c.width + 1
+	}
+	head.WriteString("LAST OUTPUT")
+	b.WriteString("\x1b[2m" + fit(head.String(), width) + "\x1b[0m\r\n")
+
+	// Header, column titles, legend and message take four lines
+	visible := height - 4
+	if visible < 1 {
+		visible = 1
+	}
+	start := 0
+	if selected >= visible {
+		start = selected - visible + 1
+	}
+
+	for i := start; i < len(rows) && i < start+visible; i++ {
+		r := rows[i]
+		var line strings.Builder
+		if i == selected {
+			line.WriteString("> ")
+		} else {
+			line.WriteString("  ")
+		}
+		for _, c := range columns {
+			line.WriteString(fzf.PadToDisplayWidth(truncate(c.value(r, now), c.width), c.width) + " ")
+		}
+		if rest := width - fixed; rest > 0 {
+			line.WriteString(truncate(r.LastLine, rest))
+		}
+
+		text := fit(line.String(), width)
+		if i == selected {
+			text = "\x1b[7m" + fzf.PadToDisplayWidth(text, width) + "\x1b[0m"
+		}
+		b.WriteString(text + "\r\n")
+	}
+	if len(rows) == 0 {
+		b.WriteString("  No sessions found. Create one with: claude-matrix create\r\n")
+	}
+
+	// Pin the legend and message to the bottom
+	b.WriteString(fmt.Sprintf("\x1b[%d;1H", height-1))
+	b.WriteString("\x1b[2m" + fit(legend, width) + "\x1b[0m\r\n")
+	b.WriteString(fit(message, width))
+
+	return b.String()
+}
+
+// timeInState returns how long a session has been in its Claude state
+func timeInState(r *Row, now time.Time) string {
+	if !r.Status.TmuxActive || r.Since.IsZero() {
+		return "-"
+	}
+	d := now.Sub(r.Since)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// fit cuts s to the terminal width without an ellipsis
+func fit(s string, width int) string {
+	if fzf.DisplayWidth(s) <= width {
+		return s
+	}
+	return cut(s, width)
+}
+
+// truncate shortens s to width cells, marking the cut with an ellipsis
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if fzf.DisplayWidth(s) <= width {
+		return s
+	}
+	return cut(s, width-1) + "…"
+}
+
+// cut returns the longest prefix of s that fits in width cells
+func cut(s string, width int) string {
+	w := 0
+	for i, r := range s {
+		rw := fzf.DisplayWidth(string(r))
+		if w+rw > width {
+			return s[:i]
+		}
+		w += rw
+	}
+	return s
+}
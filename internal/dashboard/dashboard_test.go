@@ -0,0 +1,138 @@
+package dashboard
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+func TestParseInput(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Input
+	}{
+		{"\x1b[A", InputUp},
+		{"k", InputUp},
+		{"\x1b[B", InputDown},
+		{"j", InputDown},
+		{"q", InputQuit},
+		{"\x1b", InputQuit},
+		{"\x03", InputQuit},
+		{"\r", InputSwitch},
+		{"\x04", InputDelete},
+		{"\x12", InputRename},
+		{"\x1bt", InputTag},
+		{"\x05", InputNotes},
+		{"\x13", InputTools},
+		{"x", InputNone},
+	}
+	for _, tt := range tests {
+		if got := ParseInput([]byte(tt.in)); got != tt.want {
+			t.Errorf("ParseInput(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func testRows(n int) []*Row {
+	rows := make([]*Row, n)
+	for i := range rows {
+		rows[i] = &Row{Status: &types.SessionStatus{
+			Session:     &types.Session{Name: fmt.Sprintf("session-%02d", i)},
+			ClaudeState: types.ClaudeStateIdle,
+		}}
+	}
+	return rows
+}
+
+func TestRenderScrollsToSelection(t *testing.T) {
+	rows := testRows(20)
+	out := Render(rows, 15, 120, 10, time.Now(), "")
+
+	// 10 lines leave room for 6 rows, so rows 10-15 are shown
+	if strings.Contains(out, "session-09") || !strings.Contains(out, "session-10") {
+		t.Errorf("expected the window to start at session-10:\n%s", out)
+	}
+	highlighted := out[strings.Index(out, "\x1b[7m"):]
+	if !strings.HasPrefix(highlighted, "\x1b[7m> ") || !strings.Contains(highlighted[:strings.Index(highlighted, "\r\n")], "session-15") {
+		t.Errorf("expected session-15 highlighted:\n%s", out)
+	}
+	if strings.Contains(out, "session-16") {
+		t.Errorf("rows past the window should be hidden:\n%s", out)
+	}
+}
+
+func TestRenderFitsWidth(t *testing.T) {
+	rows := testRows(1)
+	rows[0].Status.TmuxActive = true
+	rows[0].LastLine = strings.Repeat("output ", 50)
+
+	out := Render(rows, -1, 100, 10, time.Now(), "")
+	for _, line := range strings.Split(out, "\r\n") {
+		// Strip the cursor movement and styling before measuring
+		if i := strings.LastIndex(line, "H"); strings.HasPrefix(line, "\x1b[") && i > 0 && i < 8 {
+			line = line[i+1:]
+		}
+		for _, seq := range []string{"\x1b[0m", "\x1b[1m", "\x1b[2m", "\x1b[7m"} {
+			line = strings.ReplaceAll(line, seq, "")
+		}
+		if n := len([]rune(line)); n > 100 {
+			t.Errorf("line is %d cells wide, want at most 100: %q", n, line)
+		}
+	}
+	if !strings.Contains(out, "…") {
+		t.Error("expected the long pane line to be truncated with an ellipsis")
+	}
+}
+
+func TestRenderEmpty(t *testing.T) {
+	out := Render(nil, -1, 80, 24, time.Now(), "boom")
+	if !strings.Contains(out, "No sessions found") || !strings.Contains(out, "boom") {
+		t.Errorf("unexpected empty dashboard:\n%s", out)
+	}
+}
+
+func TestTimeInState(t *testing.T) {
+	now := time.Now()
+	row := &Row{
+		Status: &types.SessionStatus{TmuxActive: true, LastActivity: now.Add(-time.Second)},
+		Since:  now.Add(-90 * time.Second),
+	}
+	if got := timeInState(row, now); got != "1m" {
+		t.Errorf("timeInState() = %q, want 1m", got)
+	}
+	row.Since = time.Time{}
+	if got := timeInState(row, now); got != "-" {
+		t.Errorf("timeInState() without a known start = %q, want -", got)
+	}
+	row.Since = now.Add(-90 * time.Second)
+	row.Status.TmuxActive = false
+	if got := timeInState(row, now); got != "-" {
+		t.Errorf("timeInState() for an inactive session = %q, want -", got)
+	}
+}
+
+func TestDirStampChanges(t *testing.T) {
+	dir := t.TempDir()
+	before := dirStamp(dir)
+
+	if err := os.WriteFile(filepath.Join(dir, "a.state"), []byte("ready"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	written := dirStamp(dir)
+	if written == before {
+		t.Error("expected a new state file to change the stamp")
+	}
+
+	// Temp files from atomic writes are ignored
+	if err := os.WriteFile(filepath.Join(dir, "b.tmp"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if dirStamp(dir) != written {
+		t.Error("expected .tmp files not to change the stamp")
+	}
+}
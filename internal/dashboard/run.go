@@ -0,0 +1,193 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// DefaultPollInterval is how often the status dir is checked for changes
+	DefaultPollInterval = 500 * time.Millisecond
+	// DefaultRefreshInterval is how often everything is reloaded even
+	// without state changes, to pick up new pane output and git changes
+	DefaultRefreshInterval = 5 * time.Second
+)
+
+// Options configures Run
+type Options struct {
+	StatusDir string
+	// Load returns the rows to show, in display order
+	Load func() ([]*Row, error)
+	// Handle runs an action on a row with the terminal in normal mode, so
+	// it can prompt. Returning quit ends the dashboard.
+	Handle          func(in Input, row *Row) (quit bool, err error)
+	PollInterval    time.Duration
+	RefreshInterval time.Duration
+}
+
+// Run shows the live dashboard until the user quits or ctx is cancelled.
+// Rows are reloaded whenever a file in the status dir changes and every
+// RefreshInterval.
+func Run(ctx context.Context, opts Options) error {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultPollInterval
+	}
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = DefaultRefreshInterval
+	}
+
+	var term terminal
+	if err := term.enter(); err != nil {
+		return err
+	}
+	defer term.leave()
+
+	resized := make(chan os.Signal, 1)
+	signal.Notify(resized, syscall.SIGWINCH)
+	defer signal.Stop(resized)
+
+	var (
+		rows     []*Row
+		selected string // Name of the selected session, kept across reloads
+		message  string
+		loadedAt time.Time
+		polledAt time.Time
+		drawnAt  time.Time
+		stamp    string
+		redraw   bool
+	)
+	width, height := term.size()
+
+	load := func(now time.Time) {
+		loaded, err := opts.Load()
+		if err != nil {
+			message = "⚠️  " + err.Error()
+		} else {
+			rows = loaded
+		}
+		loadedAt, redraw = now, true
+	}
+
+	buf := make([]byte, 32)
+	for ctx.Err() == nil {
+		now := time.Now()
+
+		if now.Sub(polledAt) >= opts.PollInterval {
+			polledAt = now
+			if s := dirStamp(opts.StatusDir); s != stamp || now.Sub(loadedAt) >= opts.RefreshInterval {
+				stamp = s
+				load(now)
+			}
+		}
+
+		select {
+		case <-resized:
+			width, height = term.size()
+			redraw = true
+		default:
+		}
+
+		idx := indexOf(rows, selected)
+		if idx < 0 && len(rows) > 0 {
+			idx = 0
+			selected = rows[0].Status.Session.Name
+		}
+
+		// The time-in-state column ticks every second
+		if redraw || now.Sub(drawnAt) >= time.Second {
+			fmt.Print("\x1b[H\x1b[J" + Render(rows, idx, width, height, now, message))
+			drawnAt, redraw = now, false
+		}
+
+		// Returns after a tenth of a second without input
+		n, _ := os.Stdin.Read(buf) //nolint:errcheck // A failed read is treated as no input
+		if n == 0 {
+			continue
+		}
+
+		in := ParseInput(buf[:n])
+		switch in {
+		case InputNone:
+			continue
+		case InputQuit:
+			return nil
+		case InputRefresh:
+			load(now)
+			continue
+		case InputUp, InputDown:
+			if idx >= 0 {
+				if in == InputUp && idx > 0 {
+					idx--
+				} else if in == InputDown && idx < len(rows)-1 {
+					idx++
+				}
+				selected = rows[idx].Status.Session.Name
+			}
+			redraw = true
+			continue
+		}
+
+		if idx < 0 && in != InputTools {
+			continue
+		}
+		var row *Row
+		if idx >= 0 {
+			row = rows[idx]
+		}
+
+		term.leave()
+		fmt.Print("\x1b[H\x1b[J")
+		quit, err := opts.Handle(in, row)
+		if quit {
+			return err
+		}
+		message = ""
+		if err != nil {
+			message = "⚠️  " + err.Error()
+		}
+		if err := term.enter(); err != nil {
+			return err
+		}
+		width, height = term.size()
+		load(time.Now())
+	}
+	return nil
+}
+
+// indexOf returns the index of the named session in rows, or -1
+func indexOf(rows []*Row, name string) int {
+	for i, r := range rows {
+		if r.Status.Session.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// dirStamp summarizes the names, sizes and modification times of the files
+// in dir, so any state change alters it
+func dirStamp(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, entry := range entries {
+		if filepath.Ext(entry.Name()) == ".tmp" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&b, "%s:%d:%d;", entry.Name(), info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String()
+}
@@ -0,0 +1,59 @@
+package dashboard
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// terminal switches the controlling terminal between the dashboard's raw
+// full-screen mode and the normal mode prompts need. It goes through stty,
+// which behaves the same on Linux and macOS.
+type terminal struct {
+	saved string // stty -g state to restore
+}
+
+// stty runs stty on the terminal behind stdin
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// enter switches to the alternate screen and raw mode. Reads return after
+// at most a tenth of a second so the caller can refresh between key presses.
+func (t *terminal) enter() error {
+	saved, err := stty("-g")
+	if err != nil {
+		return fmt.Errorf("stdin is not a terminal: %w", err)
+	}
+	t.saved = saved
+
+	if _, err := stty("raw", "-echo", "min", "0", "time", "1"); err != nil {
+		return fmt.Errorf("failed to enter raw mode: %w", err)
+	}
+	fmt.Print("\x1b[?1049h\x1b[?25l") // Alternate screen, hide cursor
+	return nil
+}
+
+// leave restores the normal screen and terminal mode
+func (t *terminal) leave() {
+	fmt.Print("\x1b[?25h\x1b[?1049l") // Show cursor, main screen
+	if t.saved != "" {
+		stty(t.saved) //nolint:errcheck // Nothing left to do if the terminal can't be restored
+	}
+}
+
+// size returns the terminal width and height, with a fallback when stty
+// can't tell
+func (t *terminal) size() (width, height int) {
+	out, err := stty("size")
+	if err == nil {
+		if _, err := fmt.Sscanf(out, "%d %d", &height, &width); err == nil && width > 0 && height > 0 {
+			return width, height
+		}
+	}
+	return 80, 24
+}
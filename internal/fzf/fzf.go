@@ -7,7 +7,9 @@ import (
 	"os/exec"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/mateimicu/tmux-claude-matrix/internal/tmux"
 	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
 )
 
@@ -15,8 +17,7 @@ import (
 // The binaryPath is used to construct the Ctrl+R reload command.
 // The path is shell-quoted to handle spaces (e.g. "/Users/First Last/bin/claude-matrix").
 func buildRepoFZFArgs(binaryPath string) []string {
-	quoted := "'" + strings.ReplaceAll(binaryPath, "'", "'\\''") + "'"
-	reloadCmd := fmt.Sprintf("%s list-repos --force-refresh", quoted)
+	reloadCmd := fmt.Sprintf("%s list-repos --force-refresh", tmux.ShellQuote(binaryPath))
 	return []string{
 		"--prompt=📁 Select repository > ",
 		"--reverse",
@@ -70,6 +71,49 @@ func SelectRepository(repos []*types.Repository, binaryPath string) (*types.Repo
 	return nil, fmt.Errorf("selected repo not found")
 }
 
+// newBranchIndicator marks the entry that creates a fresh branch in the branch list
+const newBranchIndicator = "➕"
+
+// formatBranchList formats the branch choices for FZF. The first line creates
+// newBranch; the rest check out existing branches. Each line ends with
+// [branch] for extractURL compatibility.
+func formatBranchList(newBranch string, branches []string) []string {
+	lines := []string{fmt.Sprintf("%s new       %s [%s]", newBranchIndicator, newBranch, newBranch)}
+	for _, b := range branches {
+		if b == newBranch {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("🌿 existing  %s [%s]", b, b))
+	}
+	return lines
+}
+
+// SelectBranch shows FZF interface for choosing the branch a new session
+// works on. The first entry, selected by default, creates newBranch.
+// Returns the chosen branch and whether it should be created.
+func SelectBranch(newBranch string, branches []string) (string, bool, error) {
+	lines := formatBranchList(newBranch, branches)
+
+	selected, err := runFZF(
+		strings.Join(lines, "\n"),
+		"--prompt=🌿 Select branch > ",
+		"--reverse",
+		"--border=rounded",
+		"--height=80%",
+		"--header=enter: select (first entry creates a new branch) | ctrl-c: cancel",
+	)
+	if err != nil {
+		return "", false, err
+	}
+
+	branch := extractURL(selected)
+	if branch == "" {
+		return "", false, fmt.Errorf("selected branch not found")
+	}
+
+	return branch, strings.HasPrefix(selected, newBranchIndicator), nil
+}
+
 // SessionAction represents an action to perform on a session
 type SessionAction string
 
@@ -86,12 +130,38 @@ const (
 	SessionActionRename SessionAction = "rename"
 	// SessionActionTools indicates opening the tools sub-menu
 	SessionActionTools SessionAction = "tools"
+	// SessionActionTagFilter indicates choosing the tag filter
+	SessionActionTagFilter SessionAction = "tag_filter"
+	// SessionActionTag indicates editing a session's tags
+	SessionActionTag SessionAction = "tag"
+	// SessionActionNotes indicates editing a session's notes
+	SessionActionNotes SessionAction = "notes"
+	// SessionActionCycleSort indicates switching to the next sort mode
+	SessionActionCycleSort SessionAction = "cycle_sort"
+	// SessionActionBroadcast indicates sending a prompt to the marked sessions
+	SessionActionBroadcast SessionAction = "broadcast"
 )
 
+// SessionView holds the list view state shown in the legend
+type SessionView struct {
+	ShowActiveOnly bool
+	TagFilter      string   // Only sessions with this tag are shown; empty shows all
+	SortMode       SortMode // Empty sorts by creation time
+}
+
+// PreviewOptions configures the preview pane of the session picker
+type PreviewOptions struct {
+	BinaryPath string // claude-matrix binary running the preview command
+	Window     string // FZF --preview-window spec, e.g. "right,50%,wrap"
+	ScrollUp   string // Key scrolling the preview up half a page
+	ScrollDown string // Key scrolling the preview down half a page
+}
+
 // SessionSelection represents the result of session selection
 type SessionSelection struct {
-	Session *types.SessionStatus
-	Action  SessionAction
+	Session  *types.SessionStatus
+	Sessions []*types.SessionStatus // Marked sessions, set for SessionActionBroadcast
+	Action   SessionAction
 }
 
 // FilterActiveSessions returns only sessions with TmuxActive=true.
@@ -105,30 +175,74 @@ func FilterActiveSessions(sessions []*types.SessionStatus) []*types.SessionStatu
 	return active
 }
 
-// sessionLegend returns the FZF header legend, with the ctrl-t hint
-// reflecting the current filter state.
-func sessionLegend(showActiveOnly bool) string {
+// FilterSessionsByTag returns only sessions tagged with tag.
+func FilterSessionsByTag(sessions []*types.SessionStatus, tag string) []*types.SessionStatus {
+	var tagged []*types.SessionStatus
+	for _, s := range sessions {
+		for _, t := range s.Session.Tags {
+			if t == tag {
+				tagged = append(tagged, s)
+				break
+			}
+		}
+	}
+	return tagged
+}
+
+// sessionLegend returns the FZF header legend, with the ctrl-t, ctrl-f and
+// alt-s hints reflecting the current filter and sort state.
+func sessionLegend(view SessionView) string {
 	toggleHint := "ctrl-t: hide inactive"
-	if showActiveOnly {
+	if view.ShowActiveOnly {
 		toggleHint = "ctrl-t: show all"
 	}
-	return "↑↓ navigate | enter: switch | ctrl-d: delete | ctrl-r: rename | " + toggleHint + " | ctrl-s: tools | ctrl-c: cancel\n" +
+	tagHint := "ctrl-f: filter by tag"
+	if view.TagFilter != "" {
+		tagHint = "ctrl-f: tag #" + view.TagFilter
+	}
+	sortHint := "alt-s: sort by " + string(ParseSortMode(string(view.SortMode)))
+	return "↑↓ navigate | enter: switch | ctrl-d: delete | ctrl-r: rename | alt-t: tags | ctrl-e: notes | tab: mark | alt-b: broadcast | " + toggleHint + " | " + tagHint + " | " + sortHint + " | ctrl-s: tools | ctrl-c: cancel\n" +
 		"Session: 🟢 active  ⚫ inactive | Claude: 🟢 Active  ❓ Waiting  💬 Ready  ⚠️ Error  ⚫ Stopped  ❔ Unknown"
 }
 
+// buildSessionFZFArgs returns the FZF arguments for session selection.
+// The preview command receives the "[name]" field that ends each line.
+func buildSessionFZFArgs(view SessionView, preview PreviewOptions) []string {
+	args := []string{
+		"--prompt=🚀 Select session > ",
+		"--reverse",
+		"--border=rounded",
+		"--header=" + sessionLegend(view),
+		"--header-lines=1",
+		"--height=80%",
+		"--multi",
+		fmt.Sprintf("--preview=%s preview {-1}", tmux.ShellQuote(preview.BinaryPath)),
+	}
+	if preview.Window != "" {
+		args = append(args, "--preview-window="+preview.Window)
+	}
+	if preview.ScrollUp != "" {
+		args = append(args, fmt.Sprintf("--bind=%s:preview-half-page-up", preview.ScrollUp))
+	}
+	if preview.ScrollDown != "" {
+		args = append(args, fmt.Sprintf("--bind=%s:preview-half-page-down", preview.ScrollDown))
+	}
+	return args
+}
+
 // SelectSession shows FZF interface for session selection.
 // It re-prompts on toggle actions since the simplified API does not
 // expose filtering to the caller.
-func SelectSession(sessions []*types.SessionStatus) (*types.SessionStatus, error) {
+func SelectSession(sessions []*types.SessionStatus, preview PreviewOptions) (*types.SessionStatus, error) {
 	for {
-		selection, err := SelectSessionWithAction(sessions, false)
+		selection, err := SelectSessionWithAction(sessions, SessionView{}, preview)
 		if err != nil {
 			return nil, err
 		}
 		switch selection.Action {
 		case SessionActionCancel:
 			return nil, fmt.Errorf("selection cancelled")
-		case SessionActionToggleFilter, SessionActionTools:
+		case SessionActionToggleFilter, SessionActionTools, SessionActionTagFilter, SessionActionCycleSort, SessionActionBroadcast:
 			continue
 		default:
 			return selection.Session, nil
@@ -137,18 +251,13 @@ func SelectSession(sessions []*types.SessionStatus) (*types.SessionStatus, error
 }
 
 // SelectSessionWithAction shows FZF interface for session selection with action support.
-// showActiveOnly controls the ctrl-t legend hint text.
-func SelectSessionWithAction(sessions []*types.SessionStatus, showActiveOnly bool) (*SessionSelection, error) {
+// view controls the filter hints in the legend, preview the preview pane.
+func SelectSessionWithAction(sessions []*types.SessionStatus, view SessionView, preview PreviewOptions) (*SessionSelection, error) {
 	if len(sessions) == 0 {
 		return nil, fmt.Errorf("no sessions found")
 	}
 
-	// Sort sessions by creation time (newest first)
-	sortedSessions := make([]*types.SessionStatus, len(sessions))
-	copy(sortedSessions, sessions)
-	sort.Slice(sortedSessions, func(i, j int) bool {
-		return sortedSessions[i].Session.CreatedAt.After(sortedSessions[j].Session.CreatedAt)
-	})
+	sortedSessions := SortSessions(sessions, view.SortMode)
 
 	// Format sessions as aligned table
 	headerLine, lines := formatSessionTable(sortedSessions)
@@ -156,20 +265,41 @@ func SelectSessionWithAction(sessions []*types.SessionStatus, showActiveOnly boo
 	// Prepend header line so FZF can freeze it with --header-lines=1
 	allLines := append([]string{headerLine}, lines...)
 
-	// Run FZF with action keys
-	legend := sessionLegend(showActiveOnly)
-	key, selected, err := runFZFWithExpect(
-		strings.Join(allLines, "\n"),
-		[]string{"ctrl-d", "ctrl-t", "ctrl-r", "ctrl-s"},
-		"--prompt=🚀 Select session > ",
-		"--reverse",
-		"--border=rounded",
-		"--header="+legend,
-		"--header-lines=1",
-		"--height=80%",
-	)
+	// Keys acting on a single session record the row under the cursor, since
+	// with --multi FZF prints the marked rows instead
+	cursorFile, err := os.CreateTemp("", "claude-matrix-fzf-*")
 	if err != nil {
-		return &SessionSelection{Action: SessionActionCancel}, err
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	cursorFile.Close()
+	defer os.Remove(cursorFile.Name())
+
+	args := append(buildSessionFZFArgs(view, preview), cursorKeyBinds(cursorFile.Name())...)
+
+	// Run FZF with action keys. A cursor key pressed while no row matches the
+	// query records an empty row; like accepting an empty result, that selects
+	// nothing, so FZF is shown again.
+	var key, selected string
+	for {
+		if err := os.Truncate(cursorFile.Name(), 0); err != nil {
+			return nil, fmt.Errorf("failed to reset temp file: %w", err)
+		}
+		key, selected, err = runFZFWithExpect(
+			strings.Join(allLines, "\n"),
+			[]string{"ctrl-t", "ctrl-s", "ctrl-f", "alt-s", "alt-b"},
+			args...,
+		)
+		cursorKey, line, ok := readCursorKey(cursorFile.Name())
+		if ok && line == "" {
+			continue
+		}
+		if err != nil {
+			return &SessionSelection{Action: SessionActionCancel}, err
+		}
+		if ok {
+			key, selected = cursorKey, line
+		}
+		break
 	}
 
 	// ctrl-t toggles the active-only filter; no session needed
@@ -182,29 +312,92 @@ func SelectSessionWithAction(sessions []*types.SessionStatus, showActiveOnly boo
 		return &SessionSelection{Action: SessionActionTools}, nil
 	}
 
-	// Extract session name from selected line
-	name := extractSessionName(selected)
-
-	// Find original session
-	for _, sess := range sessions {
-		if sess.Session.Name == name {
-			var action SessionAction
-			switch key {
-			case "ctrl-d":
-				action = SessionActionDelete
-			case "ctrl-r":
-				action = SessionActionRename
-			default:
-				action = SessionActionSwitch
-			}
-			return &SessionSelection{
-				Session: sess,
-				Action:  action,
-			}, nil
+	// ctrl-f picks the tag filter; no session needed
+	if key == "ctrl-f" {
+		return &SessionSelection{Action: SessionActionTagFilter}, nil
+	}
+
+	// alt-s cycles the sort mode; no session needed
+	if key == "alt-s" {
+		return &SessionSelection{Action: SessionActionCycleSort}, nil
+	}
+
+	// alt-b sends a prompt to all marked sessions; FZF prints the marked
+	// rows, or the current one when nothing is marked
+	if key == "alt-b" {
+		marked := selectedSessions(sessions, selected)
+		if len(marked) == 0 {
+			return nil, fmt.Errorf("selected session not found")
 		}
+		return &SessionSelection{Sessions: marked, Action: SessionActionBroadcast}, nil
+	}
+
+	// Other keys act on the row under the cursor, recorded by their binding
+	current := selectedSessions(sessions, selected)
+	if len(current) != 1 {
+		return nil, fmt.Errorf("selected session not found")
+	}
+
+	var action SessionAction
+	switch key {
+	case "ctrl-d":
+		action = SessionActionDelete
+	case "ctrl-r":
+		action = SessionActionRename
+	case "alt-t":
+		action = SessionActionTag
+	case "ctrl-e":
+		action = SessionActionNotes
+	default:
+		action = SessionActionSwitch
 	}
+	return &SessionSelection{
+		Session: current[0],
+		Action:  action,
+	}, nil
+}
+
+// cursorKeys are the session picker keys acting on the row under the cursor
+var cursorKeys = []string{"enter", "ctrl-d", "ctrl-r", "alt-t", "ctrl-e"}
 
-	return nil, fmt.Errorf("selected session not found")
+// cursorKeyBinds binds each of cursorKeys to write the key and the current
+// row to path before accepting
+func cursorKeyBinds(path string) []string {
+	binds := make([]string, len(cursorKeys))
+	for i, key := range cursorKeys {
+		binds[i] = fmt.Sprintf("--bind=%s:execute-silent(printf '%%s\\n' %s {} > %s)+accept", key, key, tmux.ShellQuote(path))
+	}
+	return binds
+}
+
+// readCursorKey returns the key and row written by a cursorKeyBinds binding.
+// ok is false when another key ended FZF; line is empty when no row matched.
+func readCursorKey(path string) (key, line string, ok bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", false
+	}
+	key, line, _ = strings.Cut(strings.TrimRight(string(data), "\n"), "\n")
+	if key == "" {
+		return "", "", false
+	}
+	return key, line, true
+}
+
+// selectedSessions returns the sessions of the selected FZF lines, in order.
+// Lines that don't match a session are skipped.
+func selectedSessions(sessions []*types.SessionStatus, selected string) []*types.SessionStatus {
+	var marked []*types.SessionStatus
+	for _, line := range strings.Split(selected, "\n") {
+		name := extractSessionName(strings.TrimSpace(line))
+		for _, sess := range sessions {
+			if sess.Session.Name == name {
+				marked = append(marked, sess)
+				break
+			}
+		}
+	}
+	return marked
 }
 
 // ToolAction represents an action in the tools sub-menu
@@ -241,6 +434,62 @@ func SelectToolAction() (ToolAction, error) {
 	return ToolActionCancel, nil
 }
 
+// allTagsLabel is the tag menu entry that clears the tag filter
+const allTagsLabel = "✱ all sessions"
+
+// formatTagList builds the tag menu lines: clearing the filter first, then
+// each tag with the number of sessions carrying it. The current filter is
+// marked.
+func formatTagList(counts map[string]int, current string) []string {
+	tags := make([]string, 0, len(counts))
+	for tag := range counts {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	lines := []string{allTagsLabel}
+	for _, tag := range tags {
+		marker := "  "
+		if tag == current {
+			marker = "▸ "
+		}
+		lines = append(lines, fmt.Sprintf("%s#%s (%d) [%s]", marker, tag, counts[tag], tag))
+	}
+	return lines
+}
+
+// SelectTag shows an FZF menu of the tags used by sessions and returns the
+// chosen one, or an empty string to show all sessions. Returns current if
+// the user exits FZF without selecting.
+func SelectTag(sessions []*types.SessionStatus, current string) (string, error) {
+	counts := make(map[string]int)
+	for _, s := range sessions {
+		for _, tag := range s.Session.Tags {
+			counts[tag]++
+		}
+	}
+	if len(counts) == 0 {
+		return "", fmt.Errorf("no session has tags yet; add some with alt-t")
+	}
+
+	selected, err := runFZF(
+		strings.Join(formatTagList(counts, current), "\n"),
+		"--prompt=🏷️  Filter by tag > ",
+		"--reverse",
+		"--border=rounded",
+		"--header=enter: select | ctrl-c: back",
+		"--height=50%",
+	)
+	if err != nil {
+		// Cancelled: keep the current filter
+		return current, nil
+	}
+	if selected == allTagsLabel {
+		return "", nil
+	}
+	return extractSessionName(selected), nil
+}
+
 // repoTypeLabel returns the emoji+label string for a repository's source type.
 func repoTypeLabel(repo *types.Repository) string {
 	if repo.IsWorkspace {
@@ -269,9 +518,9 @@ func FormatRepoTable(repos []*types.Repository) (string, []string) {
 
 	// Pre-compute row data and track max column widths
 	var rows []rowData
-	maxTypeW := displayWidth("TYPE")
-	maxNameW := displayWidth("ORG/REPO")
-	maxDescW := displayWidth("DESCRIPTION")
+	maxTypeW := DisplayWidth("TYPE")
+	maxNameW := DisplayWidth("ORG/REPO")
+	maxDescW := DisplayWidth("DESCRIPTION")
 
 	for _, repo := range repos {
 		typeLabel := repoTypeLabel(repo)
@@ -289,31 +538,31 @@ func FormatRepoTable(repos []*types.Repository) (string, []string) {
 		}
 		rows = append(rows, row)
 
-		if w := displayWidth(typeLabel); w > maxTypeW {
+		if w := DisplayWidth(typeLabel); w > maxTypeW {
 			maxTypeW = w
 		}
-		if w := displayWidth(repo.Name); w > maxNameW {
+		if w := DisplayWidth(repo.Name); w > maxNameW {
 			maxNameW = w
 		}
-		if w := displayWidth(repo.Description); w > maxDescW {
+		if w := DisplayWidth(repo.Description); w > maxDescW {
 			maxDescW = w
 		}
 	}
 
 	// Build header — trailing space matches the space before [identifier] in data lines
 	header := fmt.Sprintf(" %s  %s  %s ",
-		padToDisplayWidth("TYPE", maxTypeW),
-		padToDisplayWidth("ORG/REPO", maxNameW),
-		padToDisplayWidth("DESCRIPTION", maxDescW),
+		PadToDisplayWidth("TYPE", maxTypeW),
+		PadToDisplayWidth("ORG/REPO", maxNameW),
+		PadToDisplayWidth("DESCRIPTION", maxDescW),
 	)
 
 	// Build data lines
 	var lines []string
 	for _, r := range rows {
 		line := fmt.Sprintf(" %s  %s  %s [%s]",
-			padToDisplayWidth(r.typeCol, maxTypeW),
-			padToDisplayWidth(r.name, maxNameW),
-			padToDisplayWidth(r.desc, maxDescW),
+			PadToDisplayWidth(r.typeCol, maxTypeW),
+			PadToDisplayWidth(r.name, maxNameW),
+			PadToDisplayWidth(r.desc, maxDescW),
 			r.identifier,
 		)
 		lines = append(lines, line)
@@ -336,21 +585,25 @@ func formatSessionTable(sessions []*types.SessionStatus) (string, []string) {
 		source  string
 		repo    string
 		title   string
+		branch  string
+		tags    string
 		claude  string
 		session string
 	}
 
 	// Pre-compute row data and track max column widths
 	var rows []rowData
-	maxSourceW := displayWidth("SOURCE")
-	maxRepoW := displayWidth("REPOSITORY")
-	maxTitleW := displayWidth("TITLE")
-	maxClaudeW := displayWidth("CLAUDE")
+	maxSourceW := DisplayWidth("SOURCE")
+	maxRepoW := DisplayWidth("REPOSITORY")
+	maxTitleW := DisplayWidth("TITLE")
+	maxBranchW := DisplayWidth("BRANCH")
+	maxTagsW := DisplayWidth("TAGS")
+	maxClaudeW := DisplayWidth("CLAUDE")
 
 	for idx, s := range sessions {
 		source, orgRepo := parseRepoURL(s.Session.RepoURL)
-		claudeIndicator := getClaudeStatusIndicator(s.ClaudeState)
-		claudeLabel := getClaudeStateLabel(s.ClaudeState)
+		claudeIndicator := ClaudeStatusIndicator(s.ClaudeState)
+		claudeLabel := ClaudeStateLabel(s.ClaudeState)
 
 		tmux := "⚫"
 		if s.TmuxActive {
@@ -363,6 +616,16 @@ func formatSessionTable(sessions []*types.SessionStatus) (string, []string) {
 			title = s.Session.Name
 		}
 
+		branch := s.Session.Branch
+		if branch == "" {
+			branch = "-"
+		}
+
+		tags := "-"
+		if len(s.Session.Tags) > 0 {
+			tags = "#" + strings.Join(s.Session.Tags, " #")
+		}
+
 		claudeCol := claudeIndicator + " " + claudeLabel
 
 		row := rowData{
@@ -371,46 +634,58 @@ func formatSessionTable(sessions []*types.SessionStatus) (string, []string) {
 			source:  source,
 			repo:    orgRepo,
 			title:   title,
+			branch:  branch,
+			tags:    tags,
 			claude:  claudeCol,
 			session: s.Session.Name,
 		}
 		rows = append(rows, row)
 
-		if w := displayWidth(source); w > maxSourceW {
+		if w := DisplayWidth(source); w > maxSourceW {
 			maxSourceW = w
 		}
-		if w := displayWidth(orgRepo); w > maxRepoW {
+		if w := DisplayWidth(orgRepo); w > maxRepoW {
 			maxRepoW = w
 		}
-		if w := displayWidth(title); w > maxTitleW {
+		if w := DisplayWidth(title); w > maxTitleW {
 			maxTitleW = w
 		}
-		if w := displayWidth(claudeCol); w > maxClaudeW {
+		if w := DisplayWidth(branch); w > maxBranchW {
+			maxBranchW = w
+		}
+		if w := DisplayWidth(tags); w > maxTagsW {
+			maxTagsW = w
+		}
+		if w := DisplayWidth(claudeCol); w > maxClaudeW {
 			maxClaudeW = w
 		}
 	}
 
 	// Build header
-	header := fmt.Sprintf(" %s  %s  %s  %s  %s  %s  %s",
-		padToDisplayWidth("#", paddingWidth),
-		padToDisplayWidth("TMUX", 4),
-		padToDisplayWidth("SOURCE", maxSourceW),
-		padToDisplayWidth("REPOSITORY", maxRepoW),
-		padToDisplayWidth("TITLE", maxTitleW),
-		padToDisplayWidth("CLAUDE", maxClaudeW),
+	header := fmt.Sprintf(" %s  %s  %s  %s  %s  %s  %s  %s  %s",
+		PadToDisplayWidth("#", paddingWidth),
+		PadToDisplayWidth("TMUX", 4),
+		PadToDisplayWidth("SOURCE", maxSourceW),
+		PadToDisplayWidth("REPOSITORY", maxRepoW),
+		PadToDisplayWidth("TITLE", maxTitleW),
+		PadToDisplayWidth("BRANCH", maxBranchW),
+		PadToDisplayWidth("TAGS", maxTagsW),
+		PadToDisplayWidth("CLAUDE", maxClaudeW),
 		"SESSION",
 	)
 
 	// Build data lines
 	var lines []string
 	for _, r := range rows {
-		line := fmt.Sprintf(" %s  %s  %s  %s  %s  %s  [%s]",
+		line := fmt.Sprintf(" %s  %s  %s  %s  %s  %s  %s  %s  [%s]",
 			r.num,
-			padToDisplayWidth(r.tmux, 4),
-			padToDisplayWidth(r.source, maxSourceW),
-			padToDisplayWidth(r.repo, maxRepoW),
-			padToDisplayWidth(r.title, maxTitleW),
-			padToDisplayWidth(r.claude, maxClaudeW),
+			PadToDisplayWidth(r.tmux, 4),
+			PadToDisplayWidth(r.source, maxSourceW),
+			PadToDisplayWidth(r.repo, maxRepoW),
+			PadToDisplayWidth(r.title, maxTitleW),
+			PadToDisplayWidth(r.branch, maxBranchW),
+			PadToDisplayWidth(r.tags, maxTagsW),
+			PadToDisplayWidth(r.claude, maxClaudeW),
 			r.session,
 		)
 		lines = append(lines, line)
@@ -419,8 +694,81 @@ func formatSessionTable(sessions []*types.SessionStatus) (string, []string) {
 	return header, lines
 }
 
-// getClaudeStatusIndicator returns the emoji indicator for Claude state
-func getClaudeStatusIndicator(state types.ClaudeState) string {
+// FormatSessionPreview renders the preview pane of a session: its details,
+// its notes and the last lines of its Claude pane. now is used to show the
+// session's age.
+func FormatSessionPreview(s *types.SessionStatus, notes, pane string, now time.Time) string {
+	sess := s.Session
+
+	var b strings.Builder
+	title := sess.Title
+	if title == "" {
+		title = sess.Name
+	}
+	fmt.Fprintf(&b, "%s\n\n", title)
+	fmt.Fprintf(&b, "Session:  %s\n", sess.Name)
+
+	repos := sess.RepoURLs
+	if name, ok := strings.CutPrefix(sess.RepoURL, "workspace:"); ok {
+		fmt.Fprintf(&b, "Workspace: %s\n", name)
+	} else if len(repos) == 0 && sess.RepoURL != "" {
+		repos = []string{sess.RepoURL}
+	}
+	for _, url := range repos {
+		fmt.Fprintf(&b, "Repo:     %s\n", url)
+	}
+
+	if sess.Branch != "" {
+		fmt.Fprintf(&b, "Branch:   %s\n", sess.Branch)
+	}
+	if !sess.CreatedAt.IsZero() {
+		fmt.Fprintf(&b, "Created:  %s (%s)\n", sess.CreatedAt.Local().Format("2006-01-02 15:04"), FormatAge(now.Sub(sess.CreatedAt)))
+	}
+
+	claude := ClaudeStatusIndicator(s.ClaudeState) + " " + ClaudeStateLabel(s.ClaudeState)
+	if !s.TmuxActive {
+		claude += " (tmux session inactive)"
+	}
+	fmt.Fprintf(&b, "Claude:   %s\n", claude)
+
+	if len(sess.Tags) > 0 {
+		fmt.Fprintf(&b, "Tags:     #%s\n", strings.Join(sess.Tags, " #"))
+	}
+	if sess.Prompt != "" {
+		fmt.Fprintf(&b, "Task:     %s\n", sess.Prompt)
+	}
+
+	b.WriteString("\n─── Notes ───\n")
+	if strings.TrimSpace(notes) == "" {
+		b.WriteString("No notes yet, press ctrl-e to add some.\n")
+	} else {
+		b.WriteString(strings.TrimRight(notes, "\n") + "\n")
+	}
+
+	if pane != "" {
+		b.WriteString("\n─── Claude ───\n")
+		b.WriteString(pane)
+	}
+
+	return b.String()
+}
+
+// FormatAge returns a short human-readable form of d, like "3h ago"
+func FormatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+// ClaudeStatusIndicator returns the emoji indicator for Claude state
+func ClaudeStatusIndicator(state types.ClaudeState) string {
 	switch state {
 	case types.ClaudeStateRunning:
 		return "🟢"
@@ -437,8 +785,8 @@ func getClaudeStatusIndicator(state types.ClaudeState) string {
 	}
 }
 
-// getClaudeStateLabel returns a short label for the Claude state
-func getClaudeStateLabel(state types.ClaudeState) string {
+// ClaudeStateLabel returns a short label for the Claude state
+func ClaudeStateLabel(state types.ClaudeState) string {
 	switch state {
 	case types.ClaudeStateRunning:
 		return "Active"
@@ -455,9 +803,9 @@ func getClaudeStateLabel(state types.ClaudeState) string {
 	}
 }
 
-// displayWidth returns the display width of a string, accounting for
+// DisplayWidth returns the display width of a string, accounting for
 // wide characters like emojis that take 2 terminal cells.
-func displayWidth(s string) int {
+func DisplayWidth(s string) int {
 	w := 0
 	for _, r := range s {
 		if r == 0xFE0F { // variation selector-16, zero-width
@@ -480,9 +828,9 @@ func isWideRune(r rune) bool {
 		(r >= 0x2B50 && r <= 0x2B55) // Stars
 }
 
-// padToDisplayWidth pads a string with spaces to reach the target display width.
-func padToDisplayWidth(s string, width int) string {
-	dw := displayWidth(s)
+// PadToDisplayWidth pads a string with spaces to reach the target display width.
+func PadToDisplayWidth(s string, width int) string {
+	dw := DisplayWidth(s)
 	if dw >= width {
 		return s
 	}
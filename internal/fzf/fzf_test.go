@@ -1,10 +1,13 @@
 package fzf
 
 import (
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/mateimicu/tmux-claude-matrix/internal/tmux"
 	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
 )
 
@@ -88,9 +91,9 @@ func TestGetClaudeStatusIndicator(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := getClaudeStatusIndicator(tt.state)
+			result := ClaudeStatusIndicator(tt.state)
 			if result != tt.expected {
-				t.Errorf("getClaudeStatusIndicator(%q) = %q, expected %q",
+				t.Errorf("ClaudeStatusIndicator(%q) = %q, expected %q",
 					tt.state, result, tt.expected)
 			}
 		})
@@ -113,9 +116,9 @@ func TestGetClaudeStateLabel(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := getClaudeStateLabel(tt.state)
+			result := ClaudeStateLabel(tt.state)
 			if result != tt.expected {
-				t.Errorf("getClaudeStateLabel(%q) = %q, expected %q",
+				t.Errorf("ClaudeStateLabel(%q) = %q, expected %q",
 					tt.state, result, tt.expected)
 			}
 		})
@@ -128,6 +131,7 @@ func TestFormatSessionTable(t *testing.T) {
 			Session: &types.Session{
 				Name:      "test-session-1",
 				RepoURL:   "https://github.com/mateimicu/tmux-claude-fleet",
+				Branch:    "claude/test-session-1",
 				CreatedAt: time.Now(),
 			},
 			TmuxActive:  true,
@@ -147,7 +151,7 @@ func TestFormatSessionTable(t *testing.T) {
 	header, lines := formatSessionTable(sessions)
 
 	// Header should contain column names including TITLE
-	for _, col := range []string{"#", "TMUX", "SOURCE", "REPOSITORY", "TITLE", "CLAUDE", "SESSION"} {
+	for _, col := range []string{"#", "TMUX", "SOURCE", "REPOSITORY", "TITLE", "BRANCH", "TAGS", "CLAUDE", "SESSION"} {
 		if !strings.Contains(header, col) {
 			t.Errorf("header %q should contain column name %q", header, col)
 		}
@@ -159,7 +163,7 @@ func TestFormatSessionTable(t *testing.T) {
 
 	// First row: active GitHub session - REPOSITORY shows orgRepo, TITLE shows session name (no Title set)
 	row1 := lines[0]
-	for _, want := range []string{"1", "🟢", "github", "mateimicu/tmux-claude-fleet", "test-session-1", "Active", "[test-session-1]"} {
+	for _, want := range []string{"1", "🟢", "github", "mateimicu/tmux-claude-fleet", "test-session-1", "claude/test-session-1", "Active", "[test-session-1]"} {
 		if !strings.Contains(row1, want) {
 			t.Errorf("row 1 %q should contain %q", row1, want)
 		}
@@ -167,7 +171,7 @@ func TestFormatSessionTable(t *testing.T) {
 
 	// Second row: inactive local session
 	row2 := lines[1]
-	for _, want := range []string{"2", "⚫", "local", "myorg/myrepo", "local-project", "Stopped", "[local-project]"} {
+	for _, want := range []string{"2", "⚫", "local", "myorg/myrepo", "local-project", " - ", "Stopped", "[local-project]"} {
 		if !strings.Contains(row2, want) {
 			t.Errorf("row 2 %q should contain %q", row2, want)
 		}
@@ -297,14 +301,14 @@ func TestFormatSessionTableAlignment(t *testing.T) {
 	// All lines should have the same display width up to the SESSION column.
 	// The SESSION column is the last one and varies in width, so check that
 	// the prefix before "[" (the session name bracket) has consistent display width.
-	headerPrefixW := displayWidth(header) - displayWidth("SESSION")
+	headerPrefixW := DisplayWidth(header) - DisplayWidth("SESSION")
 	for i, line := range lines {
 		bracketIdx := strings.LastIndex(line, "[")
 		if bracketIdx < 0 {
 			t.Fatalf("line %d missing session name bracket: %q", i, line)
 		}
 		prefix := line[:bracketIdx]
-		prefixW := displayWidth(prefix)
+		prefixW := DisplayWidth(prefix)
 		if prefixW != headerPrefixW {
 			t.Errorf("line %d prefix display width = %d, want %d (header width)\nheader: %q\nline:   %q",
 				i, prefixW, headerPrefixW, header, line)
@@ -329,9 +333,9 @@ func TestDisplayWidth(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := displayWidth(tt.input)
+			result := DisplayWidth(tt.input)
 			if result != tt.expected {
-				t.Errorf("displayWidth(%q) = %d, want %d", tt.input, result, tt.expected)
+				t.Errorf("DisplayWidth(%q) = %d, want %d", tt.input, result, tt.expected)
 			}
 		})
 	}
@@ -352,10 +356,10 @@ func TestPadToDisplayWidth(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := padToDisplayWidth(tt.input, tt.width)
-			resultW := displayWidth(result)
+			result := PadToDisplayWidth(tt.input, tt.width)
+			resultW := DisplayWidth(result)
 			if resultW != tt.expectedWidth {
-				t.Errorf("padToDisplayWidth(%q, %d) display width = %d, want %d (result=%q)",
+				t.Errorf("PadToDisplayWidth(%q, %d) display width = %d, want %d (result=%q)",
 					tt.input, tt.width, resultW, tt.expectedWidth, result)
 			}
 		})
@@ -483,14 +487,14 @@ func TestFormatRepoTableAlignment(t *testing.T) {
 
 	// All data lines should have the same display width up to the "[identifier]" bracket.
 	// The header has no bracket so measure its full width.
-	headerW := displayWidth(header)
+	headerW := DisplayWidth(header)
 	for i, line := range lines {
 		bracketIdx := strings.LastIndex(line, "[")
 		if bracketIdx < 0 {
 			t.Fatalf("line %d missing identifier bracket: %q", i, line)
 		}
 		prefix := line[:bracketIdx]
-		prefixW := displayWidth(prefix)
+		prefixW := DisplayWidth(prefix)
 		if prefixW != headerW {
 			t.Errorf("line %d prefix display width = %d, want %d (header width)\nheader: %q\nline:   %q",
 				i, prefixW, headerW, header, line)
@@ -661,6 +665,10 @@ func TestSessionActions_NoDuplicateValues(t *testing.T) {
 		SessionActionToggleFilter,
 		SessionActionRename,
 		SessionActionTools,
+		SessionActionTagFilter,
+		SessionActionTag,
+		SessionActionNotes,
+		SessionActionCycleSort,
 	} {
 		if values[action] {
 			t.Errorf("duplicate SessionAction value: %q", action)
@@ -779,7 +787,7 @@ func TestSessionLegend(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			legend := sessionLegend(tt.showActiveOnly)
+			legend := sessionLegend(SessionView{ShowActiveOnly: tt.showActiveOnly})
 			for _, want := range tt.wantContains {
 				if !strings.Contains(legend, want) {
 					t.Errorf("sessionLegend(%v) should contain %q, got %q", tt.showActiveOnly, want, legend)
@@ -796,7 +804,7 @@ func TestSessionLegend(t *testing.T) {
 
 func TestSessionLegendAlwaysContainsEmojiLegend(t *testing.T) {
 	for _, showActiveOnly := range []bool{true, false} {
-		legend := sessionLegend(showActiveOnly)
+		legend := sessionLegend(SessionView{ShowActiveOnly: showActiveOnly})
 		for _, want := range []string{"🟢 active", "⚫ inactive", "🟢 Active", "❓ Waiting", "💬 Ready", "⚠️ Error", "⚫ Stopped", "❔ Unknown"} {
 			if !strings.Contains(legend, want) {
 				t.Errorf("sessionLegend(%v) should always contain %q", showActiveOnly, want)
@@ -923,3 +931,264 @@ func TestExtractSessionName(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatBranchList(t *testing.T) {
+	lines := formatBranchList("claude/my-repo", []string{"main", "claude/my-repo", "feature/x"})
+
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines (new branch deduplicated), got %d: %v", len(lines), lines)
+	}
+
+	if !strings.HasPrefix(lines[0], newBranchIndicator) {
+		t.Errorf("first line %q should be the new branch entry", lines[0])
+	}
+
+	wantBranches := []string{"claude/my-repo", "main", "feature/x"}
+	for i, want := range wantBranches {
+		if got := extractURL(lines[i]); got != want {
+			t.Errorf("extractURL(lines[%d]) = %q, want %q", i, got, want)
+		}
+	}
+
+	for _, line := range lines[1:] {
+		if strings.HasPrefix(line, newBranchIndicator) {
+			t.Errorf("existing branch line %q should not carry the new-branch indicator", line)
+		}
+	}
+}
+
+func TestFilterSessionsByTag(t *testing.T) {
+	sessions := []*types.SessionStatus{
+		{Session: &types.Session{Name: "a", Tags: []string{"backend", "urgent"}}},
+		{Session: &types.Session{Name: "b"}},
+		{Session: &types.Session{Name: "c", Tags: []string{"backend"}}},
+		{Session: &types.Session{Name: "d", Tags: []string{"frontend"}}},
+	}
+
+	result := FilterSessionsByTag(sessions, "backend")
+	if len(result) != 2 || result[0].Session.Name != "a" || result[1].Session.Name != "c" {
+		t.Errorf("FilterSessionsByTag(backend) returned %d sessions, want a and c", len(result))
+	}
+	if result := FilterSessionsByTag(sessions, "missing"); len(result) != 0 {
+		t.Errorf("FilterSessionsByTag(missing) returned %d sessions, want 0", len(result))
+	}
+}
+
+func TestSessionLegendTagFilter(t *testing.T) {
+	if legend := sessionLegend(SessionView{}); !strings.Contains(legend, "ctrl-f: filter by tag") || !strings.Contains(legend, "alt-t: tags") {
+		t.Errorf("sessionLegend() should contain the tag hints, got %q", legend)
+	}
+	if legend := sessionLegend(SessionView{TagFilter: "backend"}); !strings.Contains(legend, "ctrl-f: tag #backend") {
+		t.Errorf("sessionLegend() should show the active tag filter, got %q", legend)
+	}
+}
+
+func TestFormatTagList(t *testing.T) {
+	lines := formatTagList(map[string]int{"urgent": 1, "backend": 3}, "urgent")
+
+	want := []string{allTagsLabel, "  #backend (3) [backend]", "▸ #urgent (1) [urgent]"}
+	if len(lines) != len(want) {
+		t.Fatalf("formatTagList() = %q, want %q", lines, want)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, lines[i], want[i])
+		}
+	}
+	if got := extractSessionName(strings.TrimSpace(lines[1])); got != "backend" {
+		t.Errorf("extractSessionName() = %q, want backend", got)
+	}
+}
+
+func TestFormatSessionTableTags(t *testing.T) {
+	sessions := []*types.SessionStatus{
+		{Session: &types.Session{Name: "a", RepoURL: "https://github.com/org/repo", Tags: []string{"backend", "urgent"}}},
+		{Session: &types.Session{Name: "b", RepoURL: "https://github.com/org/repo"}},
+	}
+
+	_, lines := formatSessionTable(sessions)
+	if !strings.Contains(lines[0], "#backend #urgent") {
+		t.Errorf("line should show tags, got %q", lines[0])
+	}
+	if extractSessionName(lines[1]) != "b" {
+		t.Errorf("untagged line should still end with the session name, got %q", lines[1])
+	}
+}
+
+func TestBuildSessionFZFArgs(t *testing.T) {
+	args := buildSessionFZFArgs(SessionView{}, PreviewOptions{
+		BinaryPath: "/Users/First Last/bin/claude-matrix",
+		Window:     "down,40%",
+		ScrollUp:   "alt-k",
+		ScrollDown: "alt-j",
+	})
+
+	for _, want := range []string{
+		"--preview='/Users/First Last/bin/claude-matrix' preview {-1}",
+		"--preview-window=down,40%",
+		"--bind=alt-k:preview-half-page-up",
+		"--bind=alt-j:preview-half-page-down",
+	} {
+		found := false
+		for _, arg := range args {
+			if arg == want {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("FZF args should contain %q, got %q", want, args)
+		}
+	}
+
+	// Unset options fall back to FZF's defaults
+	for _, arg := range buildSessionFZFArgs(SessionView{}, PreviewOptions{BinaryPath: "cm"}) {
+		if strings.HasPrefix(arg, "--preview-window=") || strings.HasPrefix(arg, "--bind=") {
+			t.Errorf("unexpected arg %q without preview options", arg)
+		}
+	}
+}
+
+func TestFormatSessionPreview(t *testing.T) {
+	now := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+	s := &types.SessionStatus{
+		Session: &types.Session{
+			Name:      "org-repo",
+			Title:     "Fix login",
+			RepoURL:   "git@github.com:org/repo.git",
+			Branch:    "claude/org-repo",
+			Tags:      []string{"backend"},
+			CreatedAt: now.Add(-3 * time.Hour),
+		},
+		TmuxActive:  true,
+		ClaudeState: types.ClaudeStateWaitingForInput,
+	}
+
+	got := FormatSessionPreview(s, "next: add a test\n", "> Allow edit to main.go?\n", now)
+	for _, want := range []string{"Fix login", "git@github.com:org/repo.git", "claude/org-repo", "(3h ago)", "❓ Waiting", "#backend", "next: add a test", "─── Claude ───", "Allow edit to main.go?"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("preview should contain %q, got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "inactive") {
+		t.Errorf("preview of an active session should not say inactive, got:\n%s", got)
+	}
+
+	// Workspaces list every repository, sessions without notes get a hint
+	s.Session.RepoURL = "workspace:platform"
+	s.Session.RepoURLs = []string{"git@github.com:org/api.git", "git@github.com:org/web.git"}
+	s.TmuxActive = false
+	got = FormatSessionPreview(s, "", "", now)
+	for _, want := range []string{"Workspace: platform", "org/api.git", "org/web.git", "tmux session inactive", "No notes yet"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("preview should contain %q, got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "─── Claude ───") {
+		t.Errorf("preview without pane output should have no Claude section, got:\n%s", got)
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{30 * time.Second, "just now"},
+		{5 * time.Minute, "5m ago"},
+		{3 * time.Hour, "3h ago"},
+		{50 * time.Hour, "2d ago"},
+	}
+	for _, tt := range tests {
+		if got := FormatAge(tt.d); got != tt.want {
+			t.Errorf("FormatAge(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestSessionLegendSortMode(t *testing.T) {
+	if legend := sessionLegend(SessionView{}); !strings.Contains(legend, "alt-s: sort by created") {
+		t.Errorf("default legend should show the creation sort, got %q", legend)
+	}
+	if legend := sessionLegend(SessionView{SortMode: SortByAttention}); !strings.Contains(legend, "alt-s: sort by attention") {
+		t.Errorf("legend should show the current sort mode, got %q", legend)
+	}
+}
+
+func TestSelectedSessions(t *testing.T) {
+	sessions := []*types.SessionStatus{
+		{Session: &types.Session{Name: "a"}},
+		{Session: &types.Session{Name: "b"}},
+		{Session: &types.Session{Name: "c"}},
+	}
+
+	got := selectedSessions(sessions, "🟢 github: org/c - 01 [c]\n🟢 github: org/a - 01 [a]\nnot a session line")
+	if len(got) != 2 || got[0].Session.Name != "c" || got[1].Session.Name != "a" {
+		t.Errorf("selectedSessions() = %v, want sessions c and a in order", got)
+	}
+
+	if got := selectedSessions(sessions, ""); len(got) != 0 {
+		t.Errorf("selectedSessions() with no selection = %v, want none", got)
+	}
+}
+
+func TestSessionLegendBroadcastHints(t *testing.T) {
+	legend := sessionLegend(SessionView{})
+	for _, want := range []string{"tab: mark", "alt-b: broadcast"} {
+		if !strings.Contains(legend, want) {
+			t.Errorf("sessionLegend() should contain %q, got %q", want, legend)
+		}
+	}
+}
+
+func TestCursorKeyBindsRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cursor")
+	if _, _, ok := readCursorKey(path); ok {
+		t.Error("readCursorKey() should report nothing before a binding ran")
+	}
+
+	binds := cursorKeyBinds(path)
+	if len(binds) != len(cursorKeys) {
+		t.Fatalf("cursorKeyBinds() = %v, want one bind per key", binds)
+	}
+	var ctrlD string
+	for _, b := range binds {
+		if strings.HasPrefix(b, "--bind=ctrl-d:") {
+			ctrlD = b
+		}
+	}
+	if !strings.HasSuffix(ctrlD, ")+accept") {
+		t.Fatalf("ctrl-d binding should accept after recording the row, got %q", ctrlD)
+	}
+
+	// Run the command the way FZF would, with {} replaced by the quoted row
+	command := strings.TrimSuffix(strings.TrimPrefix(ctrlD, "--bind=ctrl-d:execute-silent("), ")+accept")
+	row := "🟢 github: org/it's - 01 [c]"
+	command = strings.Replace(command, "{}", tmux.ShellQuote(row), 1)
+	if out, err := exec.Command("sh", "-c", command).CombinedOutput(); err != nil {
+		t.Fatalf("binding command failed: %v\n%s", err, out)
+	}
+
+	key, line, ok := readCursorKey(path)
+	if !ok || key != "ctrl-d" || line != row {
+		t.Errorf("readCursorKey() = (%q, %q, %v), want (ctrl-d, %q, true)", key, line, ok, row)
+	}
+
+	// With no row matching the query FZF replaces {} with an empty string
+	command = strings.TrimSuffix(strings.TrimPrefix(ctrlD, "--bind=ctrl-d:execute-silent("), ")+accept")
+	command = strings.Replace(command, "{}", "''", 1)
+	if out, err := exec.Command("sh", "-c", command).CombinedOutput(); err != nil {
+		t.Fatalf("binding command failed: %v\n%s", err, out)
+	}
+	key, line, ok = readCursorKey(path)
+	if !ok || key != "ctrl-d" || line != "" {
+		t.Errorf("readCursorKey() = (%q, %q, %v), want (ctrl-d, \"\", true)", key, line, ok)
+	}
+}
+
+func TestBuildRepoFZFArgsSingleSelection(t *testing.T) {
+	for _, arg := range buildRepoFZFArgs("cm") {
+		if arg == "--multi" {
+			t.Error("repository picker should select a single repository")
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package fzf
+
+import (
+	"sort"
+
+	"github.com/mateimicu/tmux-claude-matrix/internal/status"
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+// SortMode is the order of the session list
+type SortMode string
+
+const (
+	// SortByCreated lists the newest sessions first
+	SortByCreated SortMode = "created"
+	// SortByAttention lists sessions that need the user first, the longest
+	// waiting on top
+	SortByAttention SortMode = "attention"
+	// SortByActivity lists the most recently active sessions first
+	SortByActivity SortMode = "activity"
+	// SortByName lists sessions alphabetically
+	SortByName SortMode = "name"
+)
+
+// sortModes is the cycle order of the sort key
+var sortModes = []SortMode{SortByCreated, SortByAttention, SortByActivity, SortByName}
+
+// ParseSortMode returns the sort mode named s, falling back to SortByCreated
+// for unknown or empty names
+func ParseSortMode(s string) SortMode {
+	for _, m := range sortModes {
+		if string(m) == s {
+			return m
+		}
+	}
+	return SortByCreated
+}
+
+// Next returns the sort mode that follows m in the cycle
+func (m SortMode) Next() SortMode {
+	for i, mode := range sortModes {
+		if mode == m {
+			return sortModes[(i+1)%len(sortModes)]
+		}
+	}
+	return SortByCreated
+}
+
+// SortSessions returns a copy of sessions in the order of mode. Ties are
+// broken by name so the order is stable between refreshes.
+func SortSessions(sessions []*types.SessionStatus, mode SortMode) []*types.SessionStatus {
+	sorted := make([]*types.SessionStatus, len(sessions))
+	copy(sorted, sessions)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		switch mode {
+		case SortByAttention:
+			pa, pb := status.StatePriority(a.ClaudeState), status.StatePriority(b.ClaudeState)
+			if pa != pb {
+				return pa > pb
+			}
+			if !a.LastActivity.Equal(b.LastActivity) {
+				return activityLess(a, b, true)
+			}
+		case SortByActivity:
+			if !a.LastActivity.Equal(b.LastActivity) {
+				return activityLess(a, b, false)
+			}
+		case SortByName:
+			// Names are unique, handled below
+		default:
+			if !a.Session.CreatedAt.Equal(b.Session.CreatedAt) {
+				return a.Session.CreatedAt.After(b.Session.CreatedAt)
+			}
+		}
+		return a.Session.Name < b.Session.Name
+	})
+
+	return sorted
+}
+
+// activityLess orders a before b by last activity, oldest first if oldest
+// is set and newest first otherwise. Sessions without known activity come
+// last either way.
+func activityLess(a, b *types.SessionStatus, oldest bool) bool {
+	switch {
+	case a.LastActivity.IsZero():
+		return false
+	case b.LastActivity.IsZero():
+		return true
+	case oldest:
+		return a.LastActivity.Before(b.LastActivity)
+	default:
+		return a.LastActivity.After(b.LastActivity)
+	}
+}
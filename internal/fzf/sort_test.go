@@ -0,0 +1,79 @@
+package fzf
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+func TestSortSessions(t *testing.T) {
+	now := time.Now()
+	mk := func(name string, created, active time.Duration, state types.ClaudeState) *types.SessionStatus {
+		s := &types.SessionStatus{
+			Session:     &types.Session{Name: name, CreatedAt: now.Add(-created)},
+			ClaudeState: state,
+		}
+		if active > 0 {
+			s.LastActivity = now.Add(-active)
+		}
+		return s
+	}
+	sessions := []*types.SessionStatus{
+		mk("bravo", 3*time.Hour, 5*time.Minute, types.ClaudeStateRunning),
+		mk("alpha", 1*time.Hour, 2*time.Minute, types.ClaudeStateWaitingForInput),
+		mk("delta", 4*time.Hour, 0, types.ClaudeStateStopped),
+		mk("charlie", 2*time.Hour, 20*time.Minute, types.ClaudeStateWaitingForInput),
+		mk("echo", 5*time.Hour, 1*time.Minute, types.ClaudeStateError),
+	}
+
+	tests := []struct {
+		mode SortMode
+		want []string
+	}{
+		{SortByCreated, []string{"alpha", "charlie", "bravo", "delta", "echo"}},
+		{SortByAttention, []string{"echo", "charlie", "alpha", "bravo", "delta"}},
+		{SortByActivity, []string{"echo", "alpha", "bravo", "charlie", "delta"}},
+		{SortByName, []string{"alpha", "bravo", "charlie", "delta", "echo"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			var got []string
+			for _, s := range SortSessions(sessions, tt.mode) {
+				got = append(got, s.Session.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SortSessions(%s) = %v, want %v", tt.mode, got, tt.want)
+			}
+		})
+	}
+
+	if sessions[0].Session.Name != "bravo" {
+		t.Error("SortSessions() should not reorder its input")
+	}
+}
+
+func TestSortModeCycle(t *testing.T) {
+	mode := ParseSortMode("")
+	if mode != SortByCreated {
+		t.Fatalf("ParseSortMode(\"\") = %q, want %q", mode, SortByCreated)
+	}
+
+	seen := map[SortMode]bool{}
+	for range sortModes {
+		seen[mode] = true
+		mode = mode.Next()
+	}
+	if mode != SortByCreated || len(seen) != len(sortModes) {
+		t.Errorf("Next() should visit every mode once before wrapping, saw %v", seen)
+	}
+
+	if got := ParseSortMode("attention"); got != SortByAttention {
+		t.Errorf("ParseSortMode(\"attention\") = %q", got)
+	}
+	if got := ParseSortMode("bogus"); got != SortByCreated {
+		t.Errorf("ParseSortMode(\"bogus\") = %q, want fallback", got)
+	}
+}
@@ -0,0 +1,208 @@
+package gc
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mateimicu/tmux-claude-matrix/internal/status"
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+// Kind identifies what a garbage item is
+type Kind string
+
+const (
+	KindMetadata    Kind = "metadata"    // Session metadata whose clone path is gone
+	KindStatusFile  Kind = "status-file" // State file of a session that no longer exists
+	KindTempFile    Kind = "temp-file"   // Temp file left behind by an interrupted state or metadata write
+	KindReservation Kind = "reservation" // Expired name reservation of a create that never finished
+	KindCloneDir    Kind = "clone-dir"   // Directory in CloneDir no session references
+)
+
+// Item is a single thing gc would remove
+type Item struct {
+	Kind    Kind
+	Path    string
+	Session *types.Session // Set for KindMetadata
+}
+
+// Options holds the state gc cross-references
+type Options struct {
+	Sessions     []*types.Session // Managed sessions from the metadata dir
+	TmuxSessions []string         // Live tmux session names
+	StatusDir    string
+	SessionsDir  string
+	CloneDir     string
+	// ReservationTTL is how long a session name reservation stays valid
+	ReservationTTL time.Duration
+	// MinAge protects files and directories modified more recently, so a
+	// state write or session creation in progress is not collected
+	MinAge time.Duration
+}
+
+// Scan returns everything that can be garbage collected, grouped by kind in
+// the order Metadata, StatusFile, TempFile, Reservation, CloneDir
+func Scan(opts Options) ([]Item, error) {
+	live := make(map[string]bool, len(opts.TmuxSessions))
+	for _, name := range opts.TmuxSessions {
+		live[name] = true
+	}
+
+	var items []Item
+
+	// Metadata is stale when the clone is gone and the tmux session isn't
+	// running from it anymore
+	known := make(map[string]bool, len(opts.Sessions))
+	for _, sess := range opts.Sessions {
+		if sess.ClonePath != "" && !live[sess.Name] && !exists(sess.ClonePath) {
+			items = append(items, Item{Kind: KindMetadata, Path: sess.ClonePath, Session: sess})
+			continue
+		}
+		known[sess.Name] = true
+	}
+
+	statusItems, err := scanStatusDir(opts.StatusDir, known, live, opts.MinAge)
+	if err != nil {
+		return nil, err
+	}
+	items = append(items, statusItems...)
+
+	sessionItems, err := scanSessionsDir(opts.SessionsDir, opts.ReservationTTL, opts.MinAge)
+	if err != nil {
+		return nil, err
+	}
+	items = append(items, sessionItems...)
+
+	cloneItems, err := scanCloneDir(opts.CloneDir, opts.Sessions, live, opts.MinAge)
+	if err != nil {
+		return nil, err
+	}
+	items = append(items, cloneItems...)
+
+	return items, nil
+}
+
+// scanStatusDir finds state files of sessions that are neither managed nor
+// running in tmux, and old temp files
+func scanStatusDir(statusDir string, known, live map[string]bool, minAge time.Duration) ([]Item, error) {
+	entries, err := os.ReadDir(statusDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var stateFiles, tempFiles []Item
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(statusDir, entry.Name())
+
+		if strings.HasSuffix(entry.Name(), ".tmp") {
+			if olderThan(entry, minAge) {
+				tempFiles = append(tempFiles, Item{Kind: KindTempFile, Path: path})
+			}
+			continue
+		}
+
+		sessionName, ok := status.SessionFromFileName(entry.Name())
+		if !ok || known[sessionName] || live[sessionName] {
+			continue
+		}
+		stateFiles = append(stateFiles, Item{Kind: KindStatusFile, Path: path})
+	}
+
+	return append(stateFiles, tempFiles...), nil
+}
+
+// scanSessionsDir finds temp files of interrupted metadata writes and name
+// reservations that expired without the session being saved
+func scanSessionsDir(sessionsDir string, reservationTTL, minAge time.Duration) ([]Item, error) {
+	entries, err := os.ReadDir(sessionsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var tempFiles, reservations []Item
+	for _, entry := range entries {
+		if entry.IsDir() || !olderThan(entry, minAge) {
+			continue
+		}
+		path := filepath.Join(sessionsDir, entry.Name())
+
+		switch filepath.Ext(entry.Name()) {
+		case ".tmp":
+			tempFiles = append(tempFiles, Item{Kind: KindTempFile, Path: path})
+		case ".reserved":
+			if olderThan(entry, reservationTTL) {
+				reservations = append(reservations, Item{Kind: KindReservation, Path: path})
+			}
+		}
+	}
+
+	return append(tempFiles, reservations...), nil
+}
+
+// scanCloneDir finds directories in cloneDir that no session lives in
+func scanCloneDir(cloneDir string, sessions []*types.Session, live map[string]bool, minAge time.Duration) ([]Item, error) {
+	entries, err := os.ReadDir(cloneDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var clonePaths []string
+	for _, sess := range sessions {
+		if sess.ClonePath != "" {
+			clonePaths = append(clonePaths, filepath.Clean(sess.ClonePath))
+		}
+	}
+
+	var items []Item
+	for _, entry := range entries {
+		if !entry.IsDir() || live[entry.Name()] || !olderThan(entry, minAge) {
+			continue
+		}
+		path := filepath.Join(cloneDir, entry.Name())
+		if !referenced(path, clonePaths) {
+			items = append(items, Item{Kind: KindCloneDir, Path: path})
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].Path < items[j].Path })
+	return items, nil
+}
+
+// referenced reports whether dir is, or contains, one of the clone paths
+func referenced(dir string, clonePaths []string) bool {
+	for _, p := range clonePaths {
+		if p == dir || strings.HasPrefix(p, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// olderThan reports whether entry was last modified more than age ago
+func olderThan(entry os.DirEntry, age time.Duration) bool {
+	info, err := entry.Info()
+	if err != nil {
+		return false
+	}
+	return time.Since(info.ModTime()) > age
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
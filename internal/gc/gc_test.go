@@ -0,0 +1,128 @@
+package gc
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+func touch(t *testing.T, path string, age time.Duration) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	setAge(t, path, age)
+}
+
+func mkdir(t *testing.T, path string, age time.Duration) {
+	t.Helper()
+	if err := os.MkdirAll(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+	setAge(t, path, age)
+}
+
+func setAge(t *testing.T, path string, age time.Duration) {
+	t.Helper()
+	mtime := time.Now().Add(-age)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScan(t *testing.T) {
+	tmpDir := t.TempDir()
+	statusDir := filepath.Join(tmpDir, "status")
+	sessionsDir := filepath.Join(tmpDir, "sessions")
+	cloneDir := filepath.Join(tmpDir, "repos")
+	old := time.Hour
+
+	// Managed session with its clone
+	mkdir(t, filepath.Join(cloneDir, "alive"), old)
+	touch(t, filepath.Join(statusDir, "alive.state"), old)
+	touch(t, filepath.Join(statusDir, "alive.agent.a1.state"), old)
+
+	// Managed session whose clone was removed by hand
+	touch(t, filepath.Join(statusDir, "gone.state"), old)
+
+	// Workspace session: its directory holds several clones
+	mkdir(t, filepath.Join(cloneDir, "ws", "api"), old)
+	setAge(t, filepath.Join(cloneDir, "ws"), old)
+
+	// Unmanaged tmux session running Claude keeps its state files
+	touch(t, filepath.Join(statusDir, "scratch.state"), old)
+
+	// Leftovers
+	touch(t, filepath.Join(statusDir, "deleted.state"), old)
+	touch(t, filepath.Join(statusDir, "deleted.agent.x.state"), old)
+	touch(t, filepath.Join(statusDir, "123.tmp"), old)
+	touch(t, filepath.Join(statusDir, "456.tmp"), 0) // write in progress
+	mkdir(t, filepath.Join(cloneDir, "orphan"), old)
+	mkdir(t, filepath.Join(cloneDir, "creating"), 0) // create in progress
+	touch(t, filepath.Join(sessionsDir, "789.tmp"), old)
+	touch(t, filepath.Join(sessionsDir, "012.tmp"), 0) // save in progress
+	touch(t, filepath.Join(sessionsDir, "crashed.reserved"), 2*old)
+	touch(t, filepath.Join(sessionsDir, "cloning.reserved"), old/2) // still valid
+
+	gone := &types.Session{Name: "gone", ClonePath: filepath.Join(cloneDir, "gone")}
+	opts := Options{
+		Sessions: []*types.Session{
+			{Name: "alive", ClonePath: filepath.Join(cloneDir, "alive")},
+			gone,
+			{Name: "ws", ClonePath: filepath.Join(cloneDir, "ws")},
+		},
+		TmuxSessions:   []string{"alive", "scratch"},
+		StatusDir:      statusDir,
+		SessionsDir:    sessionsDir,
+		CloneDir:       cloneDir,
+		ReservationTTL: old,
+		MinAge:         time.Minute,
+	}
+
+	items, err := Scan(opts)
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	want := []Item{
+		{Kind: KindMetadata, Path: gone.ClonePath, Session: gone},
+		{Kind: KindStatusFile, Path: filepath.Join(statusDir, "deleted.agent.x.state")},
+		{Kind: KindStatusFile, Path: filepath.Join(statusDir, "deleted.state")},
+		{Kind: KindStatusFile, Path: filepath.Join(statusDir, "gone.state")},
+		{Kind: KindTempFile, Path: filepath.Join(statusDir, "123.tmp")},
+		{Kind: KindTempFile, Path: filepath.Join(sessionsDir, "789.tmp")},
+		{Kind: KindReservation, Path: filepath.Join(sessionsDir, "crashed.reserved")},
+		{Kind: KindCloneDir, Path: filepath.Join(cloneDir, "orphan")},
+	}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("Scan() =\n%+v\nwant\n%+v", items, want)
+	}
+}
+
+func TestScan_LiveSessionKeepsMetadata(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// The clone is gone but tmux still runs the session, so nothing is
+	// collected yet
+	opts := Options{
+		Sessions:     []*types.Session{{Name: "busy", ClonePath: filepath.Join(tmpDir, "busy")}},
+		TmuxSessions: []string{"busy"},
+		StatusDir:    filepath.Join(tmpDir, "status"),
+		CloneDir:     filepath.Join(tmpDir, "repos"),
+	}
+
+	items, err := Scan(opts)
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if len(items) != 0 {
+		t.Errorf("Scan() = %+v, want no items", items)
+	}
+}
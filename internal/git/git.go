@@ -1,6 +1,9 @@
 package git
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -8,11 +11,43 @@ import (
 )
 
 // Manager handles git operations
-type Manager struct{}
+type Manager struct {
+	out io.Writer
+	// sessionBranches is the ref pattern of session branches, which only
+	// exist locally in mirrors; empty when unset
+	sessionBranches string
+}
 
-// New creates a new git Manager
+// New creates a new git Manager that forwards git output to stdout
 func New() *Manager {
-	return &Manager{}
+	return &Manager{out: os.Stdout}
+}
+
+// SetOutput sets where the output of long-running git commands is written
+func (m *Manager) SetOutput(w io.Writer) {
+	m.out = w
+}
+
+// SetSessionBranchPattern sets the name pattern of session branches, e.g.
+// "claude/{session}". Mirror updates never prune branches matching it and
+// only fast-forward them, so the commits of removed worktrees survive while
+// session branches pushed from elsewhere still arrive.
+func (m *Manager) SetSessionBranchPattern(pattern string) {
+	m.sessionBranches = sessionBranchRef(pattern)
+}
+
+// sessionBranchRef turns a branch pattern into a ref pattern matching every
+// branch it names: the part before {session} followed by a glob. A pattern
+// without a fixed prefix can't be matched and yields an empty string.
+func sessionBranchRef(pattern string) string {
+	prefix, _, found := strings.Cut(pattern, "{session}")
+	if prefix == "" {
+		return ""
+	}
+	if !found {
+		return "refs/heads/" + prefix
+	}
+	return "refs/heads/" + prefix + "*"
 }
 
 // Clone clones a repository to the specified path
@@ -23,7 +58,7 @@ func (m *Manager) Clone(url, path string) error {
 	}
 
 	cmd := exec.Command("git", "clone", url, path)
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = m.out
 	cmd.Stderr = os.Stderr
 
 	return cmd.Run()
@@ -36,7 +71,7 @@ func (m *Manager) CloneWithCache(url, path, cacheDir string) error {
 	}
 
 	mirrorPath := m.GetMirrorPath(url, cacheDir)
-	return m.cloneWithReference(url, path, mirrorPath)
+	return m.CloneWithReference(url, path, mirrorPath)
 }
 
 // EnsureMirror creates a new mirror if one doesn't exist, or updates (fetch --prune)
@@ -83,7 +118,7 @@ func (m *Manager) createMirror(url, path string) error {
 	}
 
 	cmd := exec.Command("git", "clone", "--mirror", url, path)
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = m.out
 	cmd.Stderr = os.Stderr
 
 	return cmd.Run()
@@ -91,27 +126,439 @@ func (m *Manager) createMirror(url, path string) error {
 
 // updateMirror fetches the latest objects into an existing mirror
 func (m *Manager) updateMirror(path string) error {
-	cmd := exec.Command("git", "-C", path, "fetch", "--prune")
-	cmd.Stdout = os.Stdout
+	// Fetch refuses to update a branch checked out in a worktree, so those
+	// are left out of every fetch with negative refspecs
+	branches, err := m.worktreeBranches(path)
+	if err != nil {
+		return err
+	}
+	var excluded []string
+	for _, b := range branches {
+		excluded = append(excluded, "^"+b)
+	}
+
+	args := []string{"-C", path, "fetch", "--prune"}
+	if m.sessionBranches != "" || len(excluded) > 0 {
+		args = append(args, "origin", "+refs/*:refs/*")
+		if m.sessionBranches != "" {
+			args = append(args, "^"+m.sessionBranches)
+		}
+		args = append(args, excluded...)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = m.out
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return err
+	}
+
+	if m.sessionBranches == "" {
+		return nil
+	}
+	// Session branches may only exist here, e.g. once their worktree is
+	// removed, so they are fetched without --prune. Without "+" only
+	// fast-forwards are taken: a branch pushed from elsewhere arrives, while
+	// local commits on a diverged branch are never overwritten. git reports
+	// such branches as rejected, which doesn't fail the update.
+	args = append([]string{"-C", path, "fetch", "origin", m.sessionBranches + ":" + m.sessionBranches}, excluded...)
+	cmd = exec.Command("git", args...)
+	cmd.Stdout = m.out
+	cmd.Stderr = os.Stderr
+	cmd.Run() //nolint:errcheck // Rejected non-fast-forwards are expected, see above
+	return nil
+}
+
+// AddWorktree creates a worktree at path from an existing mirror. A new
+// branch is created from the mirror's default branch; if the branch already
+// exists it is checked out as-is so earlier commits on it are never discarded.
+func (m *Manager) AddWorktree(mirrorPath, path, branch string) error {
+	// A mirror has remote.origin.mirror=true, which would turn a plain
+	// "git push" from a worktree into a mirror push of every ref.
+	if err := exec.Command("git", "-C", mirrorPath, "config", "remote.origin.mirror", "false").Run(); err != nil {
+		return fmt.Errorf("failed to configure mirror for worktrees: %w", err)
+	}
+
+	// Ensure parent directory exists
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	args := []string{"-C", mirrorPath, "worktree", "add"}
+	if m.BranchExists(mirrorPath, branch) {
+		args = append(args, path, branch)
+	} else {
+		args = append(args, "-b", branch, path, "HEAD")
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = m.out
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
+// RemoveWorktree removes the worktree at path from its mirror and prunes
+// stale worktree metadata. Unless force is set, a worktree with uncommitted
+// changes is left in place and an error is returned. The worktree's branch is
+// kept in the mirror; later mirror updates never prune or rewind it as long
+// as it matches the session branch pattern.
+func (m *Manager) RemoveWorktree(mirrorPath, path string, force bool) error {
+	if _, err := os.Stat(path); err == nil {
+		args := []string{"-C", mirrorPath, "worktree", "remove"}
+		if force {
+			args = append(args, "--force")
+		}
+		args = append(args, path)
+		cmd := exec.Command("git", args...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("git worktree remove: %s", strings.TrimSpace(string(output)))
+		}
+	}
+
+	return exec.Command("git", "-C", mirrorPath, "worktree", "prune").Run()
+}
+
+// ListBranches returns the short names of all local branches in the
+// repository at repoPath. For a mirror these are the remote's branches.
+func (m *Manager) ListBranches(repoPath string) ([]string, error) {
+	cmd := exec.Command("git", "-C", repoPath, "for-each-ref", "--format=%(refname:short)", "refs/heads")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var branches []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if b := strings.TrimSpace(line); b != "" {
+			branches = append(branches, b)
+		}
+	}
+	return branches, nil
+}
+
+// CheckoutBranch checks out branch in the clone at repoPath. With create set,
+// a new branch is started from the current HEAD; otherwise git resolves the
+// name against local and remote-tracking branches.
+func (m *Manager) CheckoutBranch(repoPath, branch string, create bool) error {
+	args := []string{"-C", repoPath, "checkout", "-q"}
+	if create {
+		args = append(args, "-b")
+	}
+	args = append(args, branch)
+
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = m.out
 	cmd.Stderr = os.Stderr
 
 	return cmd.Run()
 }
 
-// cloneWithReference clones using an existing mirror as reference
-func (m *Manager) cloneWithReference(url, path, reference string) error {
+// CreateBranch creates branch at startPoint without checking it out
+func (m *Manager) CreateBranch(repoPath, branch, startPoint string) error {
+	cmd := exec.Command("git", "-C", repoPath, "branch", branch, startPoint)
+	cmd.Stdout = m.out
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// CurrentBranch returns the branch checked out at repoPath
+func (m *Manager) CurrentBranch(repoPath string) (string, error) {
+	cmd := exec.Command("git", "-C", repoPath, "symbolic-ref", "--short", "HEAD")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// WorktreeMirror returns the repository a linked worktree at path belongs to.
+// ok is false when path is not a linked worktree.
+func (m *Manager) WorktreeMirror(path string) (mirrorPath string, ok bool) {
+	info, err := os.Stat(filepath.Join(path, ".git"))
+	if err != nil || info.IsDir() {
+		return "", false
+	}
+	cmd := exec.Command("git", "-C", path, "rev-parse", "--path-format=absolute", "--git-common-dir")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(output)), true
+}
+
+// BranchExists checks if branch exists in the repository at repoPath, either
+// as a local branch or as a branch of the origin remote
+func (m *Manager) BranchExists(repoPath, branch string) bool {
+	for _, ref := range []string{"refs/heads/" + branch, "refs/remotes/origin/" + branch} {
+		cmd := exec.Command("git", "-C", repoPath, "show-ref", "--verify", "--quiet", ref)
+		if cmd.Run() == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// worktreeBranches returns the refs of all branches checked out in worktrees
+// of the repository at repoPath
+func (m *Manager) worktreeBranches(repoPath string) ([]string, error) {
+	cmd := exec.Command("git", "-C", repoPath, "worktree", "list", "--porcelain")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseWorktreeBranches(string(output)), nil
+}
+
+// parseWorktreeBranches extracts branch refs from "git worktree list --porcelain" output
+func parseWorktreeBranches(output string) []string {
+	var branches []string
+	for _, line := range strings.Split(output, "\n") {
+		if ref, ok := strings.CutPrefix(strings.TrimSpace(line), "branch "); ok {
+			branches = append(branches, ref)
+		}
+	}
+	return branches
+}
+
+// CloneWithReference clones using an existing mirror as reference
+func (m *Manager) CloneWithReference(url, path, reference string) error {
 	// Ensure parent directory exists
 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return err
 	}
 
 	cmd := exec.Command("git", "clone", "--reference", reference, "--dissociate", url, path)
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = m.out
 	cmd.Stderr = os.Stderr
 
 	return cmd.Run()
 }
 
+// RepoStatus summarizes local work in a checkout that would be lost if the
+// checkout were removed
+type RepoStatus struct {
+	Path        string
+	Uncommitted int // Modified, staged or untracked files
+	Stashes     int
+	Unpushed    int // Commits on local branches that no remote branch contains
+}
+
+// Clean reports whether the checkout has no local work
+func (s *RepoStatus) Clean() bool {
+	return s.Uncommitted == 0 && s.Stashes == 0 && s.Unpushed == 0
+}
+
+// Summary returns a short human-readable description of the local work
+func (s *RepoStatus) Summary() string {
+	if s.Clean() {
+		return "clean"
+	}
+	var parts []string
+	if s.Uncommitted > 0 {
+		parts = append(parts, pluralize(s.Uncommitted, "uncommitted file"))
+	}
+	if s.Stashes > 0 {
+		parts = append(parts, pluralize(s.Stashes, "stash"))
+	}
+	if s.Unpushed > 0 {
+		parts = append(parts, pluralize(s.Unpushed, "unpushed commit"))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// pluralize formats a count with a noun, adding an English plural suffix
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	if strings.HasSuffix(noun, "sh") {
+		return fmt.Sprintf("%d %ses", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// CheckRepoStatus inspects the checkout at path for uncommitted changes,
+// stashes and unpushed commits
+func (m *Manager) CheckRepoStatus(path string) (*RepoStatus, error) {
+	st := &RepoStatus{Path: path}
+
+	out, err := exec.Command("git", "-C", path, "status", "--porcelain").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git status in %s: %w", path, err)
+	}
+	st.Uncommitted = countLines(string(out))
+
+	out, err = exec.Command("git", "-C", path, "stash", "list").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git stash list in %s: %w", path, err)
+	}
+	st.Stashes = countLines(string(out))
+
+	revs, err := m.localRevs(path)
+	if err != nil {
+		return nil, err
+	}
+	out, err = exec.Command("git", append([]string{"-C", path, "rev-list"}, revs...)...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git rev-list in %s: %w", path, err)
+	}
+	st.Unpushed = countLines(string(out))
+
+	return st, nil
+}
+
+// localRevs returns the revision arguments selecting commits that exist
+// only locally. A clone compares its branches with the remote-tracking
+// branches. A mirror worktree has no remote-tracking branches; the mirror's
+// other branches are the remote's, so the checked-out branch is compared
+// with those instead.
+func (m *Manager) localRevs(path string) ([]string, error) {
+	out, err := exec.Command("git", "-C", path, "for-each-ref", "--count=1", "refs/remotes").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git for-each-ref in %s: %w", path, err)
+	}
+	if strings.TrimSpace(string(out)) != "" {
+		return []string{"--branches", "--not", "--remotes"}, nil
+	}
+
+	branch, err := m.CurrentBranch(path)
+	if err != nil {
+		// Detached HEAD: everything reachable from HEAD but no branch is local work
+		return []string{"HEAD", "--not", "--branches"}, nil
+	}
+	return []string{"refs/heads/" + branch, "--not", "--exclude=" + branch, "--branches"}, nil
+}
+
+// CreateBundle writes the commits returned by localRevs to a git bundle at
+// bundlePath, together with the branches pointing at them. It returns false
+// without writing anything when there are no local commits, since git refuses
+// to create an empty bundle.
+func (m *Manager) CreateBundle(repoPath, bundlePath string) (bool, error) {
+	revs, err := m.localRevs(repoPath)
+	if err != nil {
+		return false, err
+	}
+
+	out, err := exec.Command("git", append([]string{"-C", repoPath, "rev-list", "--count"}, revs...)...).Output()
+	if err != nil {
+		return false, fmt.Errorf("git rev-list in %s: %w", repoPath, err)
+	}
+	if strings.TrimSpace(string(out)) == "0" {
+		return false, nil
+	}
+
+	args := append([]string{"-C", repoPath, "bundle", "create", "-q", bundlePath}, revs...)
+	if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		return false, fmt.Errorf("git bundle create in %s: %w: %s", repoPath, err, strings.TrimSpace(string(output)))
+	}
+	return true, nil
+}
+
+// FetchBundle fetches all branches of the bundle at bundlePath into the
+// repository at repoPath, overwriting branches of the same name. A detached
+// HEAD in the bundle is fetched as well so its commits become available.
+func (m *Manager) FetchBundle(repoPath, bundlePath string) error {
+	heads, err := exec.Command("git", "-C", repoPath, "bundle", "list-heads", bundlePath).Output()
+	if err != nil {
+		return fmt.Errorf("git bundle list-heads %s: %w", bundlePath, err)
+	}
+
+	args := []string{"-C", repoPath, "fetch", "-q", "--update-head-ok", bundlePath}
+	for _, line := range strings.Split(strings.TrimSpace(string(heads)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		switch ref := fields[1]; {
+		case strings.HasPrefix(ref, "refs/heads/"):
+			args = append(args, "+"+ref+":"+ref)
+		case ref == "HEAD":
+			args = append(args, ref)
+		}
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = m.out
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// UncommittedPatch returns a binary patch of all staged, unstaged and
+// untracked changes relative to HEAD. Ignored files are not included.
+func (m *Manager) UncommittedPatch(repoPath string) ([]byte, error) {
+	patch, err := exec.Command("git", "-C", repoPath, "diff", "--binary", "HEAD").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff in %s: %w", repoPath, err)
+	}
+
+	out, err := exec.Command("git", "-C", repoPath, "ls-files", "--others", "--exclude-standard", "-z").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git ls-files in %s: %w", repoPath, err)
+	}
+	for _, file := range strings.Split(string(out), "\x00") {
+		if file == "" {
+			continue
+		}
+		// diff --no-index exits with 1 when the files differ, which they always do
+		diff, err := exec.Command("git", "-C", repoPath, "diff", "--binary", "--no-index", "--", os.DevNull, file).Output()
+		var exitErr *exec.ExitError
+		if err != nil && !(errors.As(err, &exitErr) && exitErr.ExitCode() == 1) {
+			return nil, fmt.Errorf("git diff of untracked %s in %s: %w", file, repoPath, err)
+		}
+		patch = append(patch, diff...)
+	}
+
+	return patch, nil
+}
+
+// ApplyPatch applies a patch created by UncommittedPatch to the working tree
+// at repoPath
+func (m *Manager) ApplyPatch(repoPath, patchPath string) error {
+	output, err := exec.Command("git", "-C", repoPath, "apply", "--whitespace=nowarn", patchPath).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git apply in %s: %w: %s", repoPath, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// ForceCheckout checks out rev at repoPath, discarding working tree changes
+func (m *Manager) ForceCheckout(repoPath, rev string) error {
+	cmd := exec.Command("git", "-C", repoPath, "checkout", "-q", "-f", rev)
+	cmd.Stdout = m.out
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// HeadCommit returns the commit checked out at repoPath
+func (m *Manager) HeadCommit(repoPath string) (string, error) {
+	output, err := exec.Command("git", "-C", repoPath, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// RemoteURL returns the URL of the origin remote of the repository at
+// repoPath. For a mirror worktree this is the mirror's origin.
+func (m *Manager) RemoteURL(repoPath string) (string, error) {
+	output, err := exec.Command("git", "-C", repoPath, "remote", "get-url", "origin").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// countLines counts the non-empty lines in s
+func countLines(s string) int {
+	n := 0
+	for _, line := range strings.Split(s, "\n") {
+		if strings.TrimSpace(line) != "" {
+			n++
+		}
+	}
+	return n
+}
+
 // ExtractRepoName extracts org/repo from a git URL
 func ExtractRepoName(url string) string {
 	// Remove .git suffix
@@ -1,9 +1,11 @@
 package git
 
 import (
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -194,3 +196,503 @@ func TestExtractRepoName(t *testing.T) {
 		})
 	}
 }
+
+// initSourceRepo creates a non-bare repository with a single commit so it
+// has a default branch that worktrees can be created from.
+func initSourceRepo(t *testing.T, path string) {
+	t.Helper()
+	cmds := [][]string{
+		{"git", "init", "-q", path},
+		{"git", "-C", path, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "init"},
+	}
+	for _, args := range cmds {
+		if out, err := exec.Command(args[0], args[1:]...).CombinedOutput(); err != nil {
+			t.Fatalf("%v failed: %v\n%s", args, err, out)
+		}
+	}
+}
+
+func TestAddWorktree(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourceRepo := filepath.Join(tmpDir, "source")
+	initSourceRepo(t, sourceRepo)
+
+	cacheDir := filepath.Join(tmpDir, "cache")
+	url := "file://" + sourceRepo
+	m := New()
+
+	if _, err := m.EnsureMirror(url, cacheDir); err != nil {
+		t.Fatalf("EnsureMirror() error = %v", err)
+	}
+	mirrorPath := m.GetMirrorPath(url, cacheDir)
+
+	// Both worktrees share the single mirror
+	wt1 := filepath.Join(tmpDir, "repos", "s1")
+	wt2 := filepath.Join(tmpDir, "repos", "s2")
+	if err := m.AddWorktree(mirrorPath, wt1, "claude/s1"); err != nil {
+		t.Fatalf("AddWorktree(s1) error = %v", err)
+	}
+	if err := m.AddWorktree(mirrorPath, wt2, "claude/s2"); err != nil {
+		t.Fatalf("AddWorktree(s2) error = %v", err)
+	}
+
+	branches, err := m.worktreeBranches(mirrorPath)
+	if err != nil {
+		t.Fatalf("worktreeBranches() error = %v", err)
+	}
+	want := []string{"refs/heads/claude/s1", "refs/heads/claude/s2"}
+	if len(branches) != len(want) {
+		t.Fatalf("worktreeBranches() = %v, want %v", branches, want)
+	}
+	for i := range want {
+		if branches[i] != want[i] {
+			t.Errorf("worktreeBranches()[%d] = %q, want %q", i, branches[i], want[i])
+		}
+	}
+
+	// Updating the mirror must not prune branches that only exist locally
+	if _, err := m.EnsureMirror(url, cacheDir); err != nil {
+		t.Fatalf("EnsureMirror() error = %v", err)
+	}
+	if !m.BranchExists(mirrorPath, "claude/s1") {
+		t.Error("worktree branch claude/s1 was pruned by mirror update")
+	}
+}
+
+func TestRemoveWorktree(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourceRepo := filepath.Join(tmpDir, "source")
+	initSourceRepo(t, sourceRepo)
+
+	cacheDir := filepath.Join(tmpDir, "cache")
+	url := "file://" + sourceRepo
+	m := New()
+
+	if _, err := m.EnsureMirror(url, cacheDir); err != nil {
+		t.Fatalf("EnsureMirror() error = %v", err)
+	}
+	mirrorPath := m.GetMirrorPath(url, cacheDir)
+
+	wt := filepath.Join(tmpDir, "repos", "s1")
+	if err := m.AddWorktree(mirrorPath, wt, "claude/s1"); err != nil {
+		t.Fatalf("AddWorktree() error = %v", err)
+	}
+
+	if got, ok := m.WorktreeMirror(wt); !ok || filepath.Clean(got) != filepath.Clean(mirrorPath) {
+		t.Errorf("WorktreeMirror() = (%q, %v), want (%q, true)", got, ok, mirrorPath)
+	}
+	if _, ok := m.WorktreeMirror(sourceRepo); ok {
+		t.Error("WorktreeMirror() should be false for a regular repository")
+	}
+
+	// Dirty worktrees are left in place
+	if err := os.WriteFile(filepath.Join(wt, "untracked.txt"), []byte("wip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RemoveWorktree(mirrorPath, wt, false); err == nil {
+		t.Error("RemoveWorktree() should fail for a worktree with local changes")
+	}
+	if err := os.Remove(filepath.Join(wt, "untracked.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.RemoveWorktree(mirrorPath, wt, false); err != nil {
+		t.Fatalf("RemoveWorktree() error = %v", err)
+	}
+	if _, err := os.Stat(wt); !os.IsNotExist(err) {
+		t.Error("worktree directory should be removed")
+	}
+
+	branches, err := m.worktreeBranches(mirrorPath)
+	if err != nil {
+		t.Fatalf("worktreeBranches() error = %v", err)
+	}
+	if len(branches) != 0 {
+		t.Errorf("worktreeBranches() = %v, want none after removal", branches)
+	}
+
+	if _, ok := m.WorktreeMirror(wt); ok {
+		t.Error("WorktreeMirror() should not report a removed worktree")
+	}
+
+	// The branch is kept so its commits are not lost
+	if !m.BranchExists(mirrorPath, "claude/s1") {
+		t.Error("branch claude/s1 should be kept after worktree removal")
+	}
+}
+
+func TestEnsureMirrorKeepsRemovedWorktreeBranch(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourceRepo := filepath.Join(tmpDir, "source")
+	initSourceRepo(t, sourceRepo)
+
+	cacheDir := filepath.Join(tmpDir, "cache")
+	url := "file://" + sourceRepo
+	m := New()
+	m.SetSessionBranchPattern("claude/{session}")
+
+	if _, err := m.EnsureMirror(url, cacheDir); err != nil {
+		t.Fatalf("EnsureMirror() error = %v", err)
+	}
+	mirrorPath := m.GetMirrorPath(url, cacheDir)
+
+	wt := filepath.Join(tmpDir, "repos", "s1")
+	if err := m.AddWorktree(mirrorPath, wt, "claude/s1"); err != nil {
+		t.Fatalf("AddWorktree() error = %v", err)
+	}
+	runGit(t, wt, "commit", "-q", "--allow-empty", "-m", "unpushed work")
+	out, err := exec.Command("git", "-C", wt, "rev-parse", "HEAD").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit := strings.TrimSpace(string(out))
+
+	if err := m.RemoveWorktree(mirrorPath, wt, false); err != nil {
+		t.Fatalf("RemoveWorktree() error = %v", err)
+	}
+	if _, err := m.EnsureMirror(url, cacheDir); err != nil {
+		t.Fatalf("EnsureMirror() error = %v", err)
+	}
+
+	out, err = exec.Command("git", "-C", mirrorPath, "rev-parse", "refs/heads/claude/s1").Output()
+	if err != nil {
+		t.Fatalf("branch claude/s1 was pruned by the mirror update: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != commit {
+		t.Errorf("claude/s1 = %s, want the unpushed commit %s", got, commit)
+	}
+}
+
+func TestEnsureMirrorFetchesUpstreamSessionBranches(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourceRepo := filepath.Join(tmpDir, "source")
+	initSourceRepo(t, sourceRepo)
+
+	cacheDir := filepath.Join(tmpDir, "cache")
+	url := "file://" + sourceRepo
+	m := New()
+	m.SetOutput(io.Discard)
+	m.SetSessionBranchPattern("claude/{session}")
+
+	if _, err := m.EnsureMirror(url, cacheDir); err != nil {
+		t.Fatalf("EnsureMirror() error = %v", err)
+	}
+	mirrorPath := m.GetMirrorPath(url, cacheDir)
+
+	// A local-only session branch with unpushed work, its worktree removed
+	wt := filepath.Join(tmpDir, "repos", "s1")
+	if err := m.AddWorktree(mirrorPath, wt, "claude/s1"); err != nil {
+		t.Fatalf("AddWorktree() error = %v", err)
+	}
+	runGit(t, wt, "commit", "-q", "--allow-empty", "-m", "unpushed work")
+	local := revParse(t, wt, "HEAD")
+	if err := m.RemoveWorktree(mirrorPath, wt, false); err != nil {
+		t.Fatalf("RemoveWorktree() error = %v", err)
+	}
+
+	// A teammate pushes a session branch of their own, and a diverged one
+	// of the same name
+	runGit(t, sourceRepo, "branch", "claude/teammate")
+	runGit(t, sourceRepo, "checkout", "-q", "-b", "claude/s1")
+	runGit(t, sourceRepo, "commit", "-q", "--allow-empty", "-m", "other machine")
+
+	if _, err := m.EnsureMirror(url, cacheDir); err != nil {
+		t.Fatalf("EnsureMirror() error = %v", err)
+	}
+
+	if !m.BranchExists(mirrorPath, "claude/teammate") {
+		t.Error("upstream branch claude/teammate was not fetched into the mirror")
+	}
+	if got := revParse(t, mirrorPath, "refs/heads/claude/s1"); got != local {
+		t.Errorf("claude/s1 = %s, want the local commit %s kept", got, local)
+	}
+}
+
+// revParse returns the commit rev names in the repository at dir
+func revParse(t *testing.T, dir, rev string) string {
+	t.Helper()
+	out, err := exec.Command("git", "-C", dir, "rev-parse", rev).Output()
+	if err != nil {
+		t.Fatalf("git rev-parse %s in %s failed: %v", rev, dir, err)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestSessionBranchRef(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"claude/{session}", "refs/heads/claude/*"},
+		{"wip-{session}-x", "refs/heads/wip-*"},
+		{"{session}", ""},
+		{"", ""},
+		{"shared", "refs/heads/shared"},
+	}
+	for _, tt := range tests {
+		if got := sessionBranchRef(tt.pattern); got != tt.want {
+			t.Errorf("sessionBranchRef(%q) = %q, want %q", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestParseWorktreeBranches(t *testing.T) {
+	output := `worktree /cache/mirrors/org-repo
+bare
+
+worktree /repos/s1
+HEAD 55f58d8475320356c8c47d738bd9991b9ab4e2aa
+branch refs/heads/claude/s1
+
+worktree /repos/s2
+HEAD 55f58d8475320356c8c47d738bd9991b9ab4e2aa
+detached
+`
+	got := parseWorktreeBranches(output)
+	if len(got) != 1 || got[0] != "refs/heads/claude/s1" {
+		t.Errorf("parseWorktreeBranches() = %v, want [refs/heads/claude/s1]", got)
+	}
+}
+
+func TestCheckoutBranch(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourceRepo := filepath.Join(tmpDir, "source")
+	initSourceRepo(t, sourceRepo)
+	if err := exec.Command("git", "-C", sourceRepo, "branch", "feature").Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	cacheDir := filepath.Join(tmpDir, "cache")
+	url := "file://" + sourceRepo
+	m := New()
+
+	if _, err := m.EnsureMirror(url, cacheDir); err != nil {
+		t.Fatalf("EnsureMirror() error = %v", err)
+	}
+	mirrorPath := m.GetMirrorPath(url, cacheDir)
+
+	branches, err := m.ListBranches(mirrorPath)
+	if err != nil {
+		t.Fatalf("ListBranches() error = %v", err)
+	}
+	found := false
+	for _, b := range branches {
+		if b == "feature" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("ListBranches() = %v, want it to include %q", branches, "feature")
+	}
+
+	clonePath := filepath.Join(tmpDir, "repos", "s1")
+	if err := m.CloneWithReference(url, clonePath, mirrorPath); err != nil {
+		t.Fatalf("CloneWithReference() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		branch string
+		create bool
+	}{
+		{name: "new branch", branch: "claude/s1", create: true},
+		{name: "existing remote branch", branch: "feature", create: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := m.CheckoutBranch(clonePath, tt.branch, tt.create); err != nil {
+				t.Fatalf("CheckoutBranch(%q, %v) error = %v", tt.branch, tt.create, err)
+			}
+			got, err := m.CurrentBranch(clonePath)
+			if err != nil {
+				t.Fatalf("CurrentBranch() error = %v", err)
+			}
+			if got != tt.branch {
+				t.Errorf("CurrentBranch() = %q, want %q", got, tt.branch)
+			}
+		})
+	}
+}
+
+// runGit runs a git command in dir with a fixed identity
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	full := append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+	if out, err := exec.Command("git", full...).CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+}
+
+func TestCheckRepoStatus_Clone(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourceRepo := filepath.Join(tmpDir, "source")
+	initSourceRepo(t, sourceRepo)
+
+	clone := filepath.Join(tmpDir, "clone")
+	m := New()
+	m.SetOutput(io.Discard)
+	if err := m.Clone("file://"+sourceRepo, clone); err != nil {
+		t.Fatalf("Clone() error = %v", err)
+	}
+
+	st, err := m.CheckRepoStatus(clone)
+	if err != nil {
+		t.Fatalf("CheckRepoStatus() error = %v", err)
+	}
+	if !st.Clean() {
+		t.Errorf("fresh clone should be clean, got %s", st.Summary())
+	}
+
+	runGit(t, clone, "commit", "-q", "--allow-empty", "-m", "local 1")
+	runGit(t, clone, "commit", "-q", "--allow-empty", "-m", "local 2")
+	if err := os.WriteFile(filepath.Join(clone, "stashed.txt"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, clone, "stash", "push", "-q", "--include-untracked")
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := os.WriteFile(filepath.Join(clone, name), []byte("wip"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	st, err = m.CheckRepoStatus(clone)
+	if err != nil {
+		t.Fatalf("CheckRepoStatus() error = %v", err)
+	}
+	want := RepoStatus{Path: clone, Uncommitted: 2, Stashes: 1, Unpushed: 2}
+	if *st != want {
+		t.Errorf("CheckRepoStatus() = %+v, want %+v", *st, want)
+	}
+	if got := st.Summary(); got != "2 uncommitted files, 1 stash, 2 unpushed commits" {
+		t.Errorf("Summary() = %q", got)
+	}
+}
+
+func TestCheckRepoStatus_Worktree(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourceRepo := filepath.Join(tmpDir, "source")
+	initSourceRepo(t, sourceRepo)
+
+	cacheDir := filepath.Join(tmpDir, "cache")
+	url := "file://" + sourceRepo
+	m := New()
+	m.SetOutput(io.Discard)
+	if _, err := m.EnsureMirror(url, cacheDir); err != nil {
+		t.Fatalf("EnsureMirror() error = %v", err)
+	}
+	mirrorPath := m.GetMirrorPath(url, cacheDir)
+
+	wt := filepath.Join(tmpDir, "repos", "s1")
+	if err := m.AddWorktree(mirrorPath, wt, "claude/s1"); err != nil {
+		t.Fatalf("AddWorktree() error = %v", err)
+	}
+
+	st, err := m.CheckRepoStatus(wt)
+	if err != nil {
+		t.Fatalf("CheckRepoStatus() error = %v", err)
+	}
+	if !st.Clean() {
+		t.Errorf("fresh worktree should be clean, got %s", st.Summary())
+	}
+
+	runGit(t, wt, "commit", "-q", "--allow-empty", "-m", "agent work")
+
+	st, err = m.CheckRepoStatus(wt)
+	if err != nil {
+		t.Fatalf("CheckRepoStatus() error = %v", err)
+	}
+	if st.Unpushed != 1 {
+		t.Errorf("Unpushed = %d, want 1", st.Unpushed)
+	}
+}
+
+func TestBundleAndPatchRoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourceRepo := filepath.Join(tmpDir, "source")
+	initSourceRepo(t, sourceRepo)
+	url := "file://" + sourceRepo
+
+	m := New()
+	m.SetOutput(io.Discard)
+
+	clone := filepath.Join(tmpDir, "clone")
+	if err := m.Clone(url, clone); err != nil {
+		t.Fatalf("Clone() error = %v", err)
+	}
+
+	bundle := filepath.Join(tmpDir, "work.bundle")
+	if ok, err := m.CreateBundle(clone, bundle); err != nil || ok {
+		t.Fatalf("CreateBundle() on a clean clone = (%v, %v), want (false, nil)", ok, err)
+	}
+
+	// Local commit on a session branch, then uncommitted work on top of it
+	if err := m.CheckoutBranch(clone, "claude/s1", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(clone, "tracked.txt"), []byte("v1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, clone, "add", "tracked.txt")
+	runGit(t, clone, "commit", "-q", "-m", "agent work")
+	head, err := m.HeadCommit(clone)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"tracked.txt":      "v2\n",
+		"staged.txt":       "staged\n",
+		"dir/untracked.go": "package dir\n",
+	}
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(clone, name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(clone, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runGit(t, clone, "add", "staged.txt")
+
+	if ok, err := m.CreateBundle(clone, bundle); err != nil || !ok {
+		t.Fatalf("CreateBundle() = (%v, %v), want (true, nil)", ok, err)
+	}
+	patch, err := m.UncommittedPatch(clone)
+	if err != nil {
+		t.Fatalf("UncommittedPatch() error = %v", err)
+	}
+	patchPath := filepath.Join(tmpDir, "work.patch")
+	if err := os.WriteFile(patchPath, patch, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Restore into a fresh clone
+	restored := filepath.Join(tmpDir, "restored")
+	if err := m.Clone(url, restored); err != nil {
+		t.Fatalf("Clone() error = %v", err)
+	}
+	if err := m.FetchBundle(restored, bundle); err != nil {
+		t.Fatalf("FetchBundle() error = %v", err)
+	}
+	if err := m.ForceCheckout(restored, "claude/s1"); err != nil {
+		t.Fatalf("ForceCheckout() error = %v", err)
+	}
+	if err := m.ApplyPatch(restored, patchPath); err != nil {
+		t.Fatalf("ApplyPatch() error = %v", err)
+	}
+
+	if got, _ := m.HeadCommit(restored); got != head {
+		t.Errorf("HEAD = %s, want %s", got, head)
+	}
+	for name, want := range files {
+		got, err := os.ReadFile(filepath.Join(restored, name))
+		if err != nil {
+			t.Errorf("%s not restored: %v", name, err)
+			continue
+		}
+		if string(got) != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"io"
 	"os"
+	"time"
 
+	"github.com/mateimicu/tmux-claude-matrix/internal/session"
 	"github.com/mateimicu/tmux-claude-matrix/internal/status"
 	"github.com/mateimicu/tmux-claude-matrix/internal/tmux"
 	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
@@ -45,8 +47,11 @@ func MapEventToState(event *HookEvent) types.ClaudeState {
 }
 
 // HandleHookEvent reads a hook event from stdin and updates tmux state accordingly.
-// It writes per-agent state files and recomputes the aggregate for the session.
-func HandleHookEvent(reader io.Reader, mgr *tmux.Manager) error {
+// It writes per-agent state files, recomputes the aggregate for the session and
+// records the Claude session ID in the session metadata so the conversation can
+// be resumed when the tmux session is recreated. When the aggregate state
+// changes, notifier is told about it; a nil notifier disables notifications.
+func HandleHookEvent(reader io.Reader, mgr *tmux.Manager, sessionMgr *session.Manager, notifier *Notifier) error {
 	data, err := io.ReadAll(reader)
 	if err != nil {
 		return err
@@ -69,6 +74,10 @@ func HandleHookEvent(reader io.Reader, mgr *tmux.Manager) error {
 		return err
 	}
 
+	if event.SessionID != "" && state != types.ClaudeStateStopped {
+		recordClaudeSessionID(sessionMgr, sessionName, event.SessionID)
+	}
+
 	statusDir := status.DefaultStatusDir()
 	agentID := event.SessionID
 	if agentID == "" {
@@ -91,12 +100,21 @@ func HandleHookEvent(reader io.Reader, mgr *tmux.Manager) error {
 		}
 	}
 
+	prevState := types.ClaudeStateStopped
+	if sf, err := status.ReadState(statusDir, sessionName); err == nil {
+		prevState = types.ClaudeState(sf.State)
+	}
+
 	// Recompute aggregate from all agent files
 	aggState, err := status.UpdateAggregate(statusDir, sessionName, status.DefaultStaleThreshold)
 	if err != nil {
 		return err
 	}
 
+	if notifier != nil && aggState != prevState {
+		notifyTransition(notifier, sessionMgr, sessionName, prevState, aggState)
+	}
+
 	// Update tmux window name to reflect aggregate state
 	if aggState == types.ClaudeStateStopped {
 		_ = mgr.RenameWindowByPane(tmuxPane, "claude") //nolint:errcheck // Best-effort reset
@@ -105,3 +123,35 @@ func HandleHookEvent(reader io.Reader, mgr *tmux.Manager) error {
 	emoji := status.EmojiForState(aggState)
 	return mgr.RenameWindowByPane(tmuxPane, emoji+"claude")
 }
+
+// notifyTransition tells notifier that a session changed state, with the
+// title and repo of managed sessions. Notifications are best-effort and
+// never fail the hook.
+func notifyTransition(notifier *Notifier, sessionMgr *session.Manager, sessionName string, from, to types.ClaudeState) {
+	n := &Notification{
+		Session:   sessionName,
+		OldState:  from,
+		NewState:  to,
+		Timestamp: time.Now(),
+	}
+	if sess, err := sessionMgr.Load(sessionName); err == nil {
+		n.Title = sess.Title
+		n.RepoURL = sess.RepoURL
+	}
+	notifier.Notify(n) //nolint:errcheck // Best-effort notification
+}
+
+// recordClaudeSessionID stores the Claude session ID in the metadata of a
+// managed session. Sessions not created by claude-matrix are ignored.
+// Failures are not fatal: state tracking must keep working without metadata.
+func recordClaudeSessionID(sessionMgr *session.Manager, sessionName, claudeSessionID string) {
+	sess, err := sessionMgr.Load(sessionName)
+	if err != nil || sess.ClaudeSessionID == claudeSessionID {
+		return
+	}
+	//nolint:errcheck // Best-effort metadata update
+	sessionMgr.Update(sessionName, func(s *types.Session) error {
+		s.ClaudeSessionID = claudeSessionID
+		return nil
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/mateimicu/tmux-claude-matrix/internal/session"
 	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
 )
 
@@ -103,3 +104,29 @@ func TestParseHookEvent(t *testing.T) {
 		t.Errorf("SessionID = %q, want %q", parsed.SessionID, "sess-abc-123")
 	}
 }
+
+func TestRecordClaudeSessionID(t *testing.T) {
+	sessionMgr := session.NewManager(t.TempDir())
+	if err := sessionMgr.Save(&types.Session{Name: "org-repo", Title: "org/repo"}); err != nil {
+		t.Fatal(err)
+	}
+
+	recordClaudeSessionID(sessionMgr, "org-repo", "sess-abc-123")
+
+	sess, err := sessionMgr.Load("org-repo")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if sess.ClaudeSessionID != "sess-abc-123" {
+		t.Errorf("ClaudeSessionID = %q, want %q", sess.ClaudeSessionID, "sess-abc-123")
+	}
+	if sess.Title != "org/repo" {
+		t.Errorf("Title = %q, other fields must be preserved", sess.Title)
+	}
+
+	// Unmanaged tmux sessions must not get metadata
+	recordClaudeSessionID(sessionMgr, "not-managed", "sess-xyz")
+	if sessionMgr.Exists("not-managed") {
+		t.Error("recordClaudeSessionID should not create metadata for unmanaged sessions")
+	}
+}
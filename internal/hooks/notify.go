@@ -0,0 +1,252 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mateimicu/tmux-claude-matrix/internal/tmux"
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+// notifyTimeout bounds how long the backends of one transition may run, so
+// a slow backend never holds Claude up for long
+const notifyTimeout = 5 * time.Second
+
+// commandWaitDelay is how long a notify command's children may keep its
+// output open after the command itself was killed
+const commandWaitDelay = 500 * time.Millisecond
+
+// Notification describes a session moving to a new Claude state
+type Notification struct {
+	Session   string            `json:"session"`
+	Title     string            `json:"title,omitempty"`
+	RepoURL   string            `json:"repo_url,omitempty"`
+	OldState  types.ClaudeState `json:"old_state"`
+	NewState  types.ClaudeState `json:"new_state"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+// Message returns a one-line description of the transition
+func (n *Notification) Message() string {
+	name := n.Title
+	if name == "" {
+		name = n.Session
+	}
+
+	switch n.NewState {
+	case types.ClaudeStateWaitingForInput:
+		return name + " is waiting for input"
+	case types.ClaudeStateError:
+		return name + " hit an error"
+	case types.ClaudeStateIdle:
+		return name + " is ready for a new prompt"
+	case types.ClaudeStateRunning:
+		return name + " is running"
+	default:
+		return fmt.Sprintf("%s is %s", name, n.NewState)
+	}
+}
+
+// Backend delivers notifications to one destination
+type Backend interface {
+	Notify(ctx context.Context, n *Notification) error
+}
+
+// Notifier sends notifications for state transitions to the backends the
+// rules configure for the new state. A session is notified about the same
+// state at most once per cooldown.
+type Notifier struct {
+	rules     map[types.ClaudeState][]Backend
+	cooldown  time.Duration
+	statusDir string // Holds the {session}.notified cooldown files
+}
+
+// NewNotifier builds the notifier configured by cfg. It returns nil when no
+// rules are configured.
+func NewNotifier(cfg *types.Config, tmuxMgr *tmux.Manager, statusDir string) (*Notifier, error) {
+	if len(cfg.NotifyRules) == 0 {
+		return nil, nil
+	}
+
+	n := &Notifier{
+		rules:     make(map[types.ClaudeState][]Backend),
+		cooldown:  cfg.NotifyCooldown,
+		statusDir: statusDir,
+	}
+	for state, names := range cfg.NotifyRules {
+		if !isNotifiableState(types.ClaudeState(state)) {
+			return nil, fmt.Errorf("unknown state %q in notify rules", state)
+		}
+		for _, name := range names {
+			backend, err := newBackend(name, cfg, tmuxMgr)
+			if err != nil {
+				return nil, err
+			}
+			n.rules[types.ClaudeState(state)] = append(n.rules[types.ClaudeState(state)], backend)
+		}
+	}
+	return n, nil
+}
+
+// newBackend returns the backend called name
+func newBackend(name string, cfg *types.Config, tmuxMgr *tmux.Manager) (Backend, error) {
+	switch name {
+	case "tmux":
+		return &tmuxBackend{mgr: tmuxMgr}, nil
+	case "bell":
+		return &bellBackend{mgr: tmuxMgr}, nil
+	case "notify-send":
+		return &notifySendBackend{}, nil
+	case "webhook":
+		if cfg.NotifyWebhookURL == "" {
+			return nil, fmt.Errorf("notify backend \"webhook\" needs NOTIFY_WEBHOOK_URL")
+		}
+		return newWebhookBackend(cfg.NotifyWebhookURL, cfg.NotifyWebhookKey), nil
+	case "command":
+		if cfg.NotifyCommand == "" {
+			return nil, fmt.Errorf("notify backend \"command\" needs NOTIFY_COMMAND")
+		}
+		return &commandBackend{command: cfg.NotifyCommand}, nil
+	default:
+		return nil, fmt.Errorf("unknown notify backend %q", name)
+	}
+}
+
+// isNotifiableState reports whether state can appear in a transition
+func isNotifiableState(state types.ClaudeState) bool {
+	switch state {
+	case types.ClaudeStateRunning, types.ClaudeStateWaitingForInput,
+		types.ClaudeStateIdle, types.ClaudeStateError, types.ClaudeStateStopped:
+		return true
+	}
+	return false
+}
+
+// Notify delivers n to the backends of its new state, unless the session was
+// notified about that state within the cooldown. Backends run concurrently
+// and are cut off after notifyTimeout.
+func (nf *Notifier) Notify(n *Notification) error {
+	backends := nf.rules[n.NewState]
+	if len(backends) == 0 {
+		return nil
+	}
+
+	if !nf.claim(n.Session, n.NewState, n.Timestamp) {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+
+	errs := make([]error, len(backends))
+	var wg sync.WaitGroup
+	for i, backend := range backends {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = backend.Notify(ctx, n)
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// claim records that session is being notified about state at now. It
+// returns false if it already was within the cooldown.
+func (nf *Notifier) claim(session string, state types.ClaudeState, now time.Time) bool {
+	path := filepath.Join(nf.statusDir, session+".notified")
+
+	sent := make(map[types.ClaudeState]time.Time)
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &sent) //nolint:errcheck // A corrupt file only resets the cooldown
+	}
+
+	if last, ok := sent[state]; ok && now.Sub(last) < nf.cooldown {
+		return false
+	}
+
+	sent[state] = now
+	if data, err := json.Marshal(sent); err == nil {
+		if err := os.MkdirAll(nf.statusDir, 0o755); err == nil {
+			os.WriteFile(path, data, 0o644) //nolint:errcheck // Best-effort; worst case is a repeated notification
+		}
+	}
+	return true
+}
+
+// tmuxBackend shows the message in the status line of every attached client
+type tmuxBackend struct {
+	mgr *tmux.Manager
+}
+
+func (b *tmuxBackend) Notify(_ context.Context, n *Notification) error {
+	return b.mgr.DisplayMessageAll("claude-matrix: " + n.Message())
+}
+
+// bellBackend rings the terminal bell of every attached client
+type bellBackend struct {
+	mgr *tmux.Manager
+}
+
+func (b *bellBackend) Notify(_ context.Context, _ *Notification) error {
+	_, ttys, err := b.mgr.ListClients()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, tty := range ttys {
+		f, err := os.OpenFile(tty, os.O_WRONLY, 0)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		_, err = f.Write([]byte("\a"))
+		errs = append(errs, err, f.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// notifySendBackend raises a desktop notification with notify-send
+type notifySendBackend struct{}
+
+func (b *notifySendBackend) Notify(ctx context.Context, n *Notification) error {
+	urgency := "normal"
+	if n.NewState == types.ClaudeStateError {
+		urgency = "critical"
+	}
+	cmd := exec.CommandContext(ctx, "notify-send", "--app-name=claude-matrix", "--urgency="+urgency, "claude-matrix", n.Message())
+	return cmd.Run()
+}
+
+// commandBackend runs a user command through the shell with the
+// notification as JSON on stdin
+type commandBackend struct {
+	command string
+}
+
+func (b *commandBackend) Notify(ctx context.Context, n *Notification) error {
+	data, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", b.command)
+	cmd.Stdin = bytes.NewReader(data)
+	// Killing sh on timeout leaves its children holding the output pipe,
+	// which would block CombinedOutput until they exit
+	cmd.WaitDelay = commandWaitDelay
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("notify command failed: %w: %s", err, bytes.TrimSpace(output))
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+type fakeBackend struct {
+	mu   sync.Mutex
+	sent []*Notification
+}
+
+func (b *fakeBackend) Notify(_ context.Context, n *Notification) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sent = append(b.sent, n)
+	return nil
+}
+
+func (b *fakeBackend) count() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.sent)
+}
+
+func TestNotifierRoutesByState(t *testing.T) {
+	waiting := &fakeBackend{}
+	errored := &fakeBackend{}
+	nf := &Notifier{
+		rules: map[types.ClaudeState][]Backend{
+			types.ClaudeStateWaitingForInput: {waiting},
+			types.ClaudeStateError:           {errored},
+		},
+		statusDir: t.TempDir(),
+	}
+
+	now := time.Now()
+	for _, state := range []types.ClaudeState{types.ClaudeStateRunning, types.ClaudeStateWaitingForInput} {
+		n := &Notification{Session: "s", OldState: types.ClaudeStateIdle, NewState: state, Timestamp: now}
+		if err := nf.Notify(n); err != nil {
+			t.Fatalf("Notify(%s) failed: %v", state, err)
+		}
+	}
+
+	if waiting.count() != 1 {
+		t.Errorf("waiting backend got %d notifications, want 1", waiting.count())
+	}
+	if errored.count() != 0 {
+		t.Errorf("error backend got %d notifications, want 0", errored.count())
+	}
+}
+
+func TestNotifierCooldown(t *testing.T) {
+	backend := &fakeBackend{}
+	nf := &Notifier{
+		rules:     map[types.ClaudeState][]Backend{types.ClaudeStateWaitingForInput: {backend}},
+		cooldown:  time.Minute,
+		statusDir: t.TempDir(),
+	}
+
+	start := time.Now()
+	notify := func(session string, at time.Time) {
+		t.Helper()
+		n := &Notification{Session: session, NewState: types.ClaudeStateWaitingForInput, Timestamp: at}
+		if err := nf.Notify(n); err != nil {
+			t.Fatalf("Notify failed: %v", err)
+		}
+	}
+
+	notify("s", start)
+	notify("s", start.Add(30*time.Second))
+	if backend.count() != 1 {
+		t.Fatalf("got %d notifications within cooldown, want 1", backend.count())
+	}
+
+	notify("other", start.Add(30*time.Second))
+	if backend.count() != 2 {
+		t.Fatalf("cooldown leaked across sessions: got %d notifications, want 2", backend.count())
+	}
+
+	notify("s", start.Add(2*time.Minute))
+	if backend.count() != 3 {
+		t.Errorf("got %d notifications after cooldown, want 3", backend.count())
+	}
+}
+
+func TestCommandBackend(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out.json")
+	backend := &commandBackend{command: "cat > " + out}
+
+	n := &Notification{
+		Session:   "s",
+		Title:     "My task",
+		OldState:  types.ClaudeStateRunning,
+		NewState:  types.ClaudeStateWaitingForInput,
+		Timestamp: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	if err := backend.Notify(context.Background(), n); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("command did not write its stdin: %v", err)
+	}
+	var got Notification
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("stdin is not a notification: %v", err)
+	}
+	if got != *n {
+		t.Errorf("got %+v, want %+v", got, *n)
+	}
+}
+
+func TestCommandBackendFailure(t *testing.T) {
+	backend := &commandBackend{command: "echo boom >&2; exit 1"}
+	err := backend.Notify(context.Background(), &Notification{Session: "s"})
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("expected error with command output, got %v", err)
+	}
+}
+
+func TestCommandBackendTimeout(t *testing.T) {
+	// The child sleep keeps the output pipe open after sh is killed
+	backend := &commandBackend{command: "sleep 30; true"}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := backend.Notify(ctx, &Notification{Session: "s"}); err == nil {
+		t.Error("expected the timed out command to fail")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Notify returned after %v, want it cut off shortly after the timeout", elapsed)
+	}
+}
+
+func TestNewNotifier(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     types.Config
+		wantNil bool
+		wantErr bool
+	}{
+		{name: "no rules", cfg: types.Config{}, wantNil: true},
+		{
+			name: "valid rules",
+			cfg: types.Config{NotifyRules: map[string][]string{
+				"waiting_for_input": {"tmux", "bell"},
+				"error":             {"notify-send"},
+			}},
+		},
+		{
+			name:    "unknown backend",
+			cfg:     types.Config{NotifyRules: map[string][]string{"error": {"pager"}}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown state",
+			cfg:     types.Config{NotifyRules: map[string][]string{"sleeping": {"tmux"}}},
+			wantErr: true,
+		},
+		{
+			name: "webhook",
+			cfg: types.Config{
+				NotifyRules:      map[string][]string{"error": {"webhook"}},
+				NotifyWebhookURL: "https://example.com/hook",
+			},
+		},
+		{
+			name:    "webhook without NOTIFY_WEBHOOK_URL",
+			cfg:     types.Config{NotifyRules: map[string][]string{"error": {"webhook"}}},
+			wantErr: true,
+		},
+		{
+			name:    "command without NOTIFY_COMMAND",
+			cfg:     types.Config{NotifyRules: map[string][]string{"error": {"command"}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nf, err := NewNotifier(&tt.cfg, nil, t.TempDir())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewNotifier() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (nf == nil) != tt.wantNil {
+				t.Errorf("NewNotifier() = %v, wantNil %v", nf, tt.wantNil)
+			}
+		})
+	}
+}
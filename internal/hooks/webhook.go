@@ -0,0 +1,116 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of a webhook body, as
+// "sha256=<hex>", when NOTIFY_WEBHOOK_SECRET is set
+const SignatureHeader = "X-Claude-Matrix-Signature"
+
+const (
+	webhookAttempts       = 3
+	webhookAttemptTimeout = 2 * time.Second
+	webhookBackoff        = 250 * time.Millisecond
+)
+
+// webhookBackend POSTs the notification as JSON to a URL. Failed deliveries
+// are retried with exponential backoff until the attempts or the notify
+// deadline run out, whichever comes first.
+type webhookBackend struct {
+	url            string
+	key            []byte
+	client         *http.Client
+	attempts       int
+	attemptTimeout time.Duration
+	backoff        time.Duration
+}
+
+func newWebhookBackend(url, key string) *webhookBackend {
+	b := &webhookBackend{
+		url:            url,
+		client:         &http.Client{},
+		attempts:       webhookAttempts,
+		attemptTimeout: webhookAttemptTimeout,
+		backoff:        webhookBackoff,
+	}
+	if key != "" {
+		b.key = []byte(key)
+	}
+	return b
+}
+
+func (b *webhookBackend) Notify(ctx context.Context, n *Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+
+	backoff := b.backoff
+	var lastErr error
+	for attempt := 1; attempt <= b.attempts; attempt++ {
+		retry, err := b.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry || attempt == b.attempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("webhook gave up: %w (last error: %v)", ctx.Err(), lastErr)
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return fmt.Errorf("webhook failed: %w", lastErr)
+}
+
+// post makes one delivery attempt. retry reports whether a failure may
+// succeed on another attempt: network errors, 429 and 5xx responses.
+func (b *webhookBackend) post(ctx context.Context, body []byte) (retry bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, b.attemptTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "claude-matrix")
+	if b.key != nil {
+		req.Header.Set(SignatureHeader, signPayload(b.key, body))
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	// Drain so the connection can be reused by the next attempt
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10)) //nolint:errcheck // Body content is irrelevant
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("webhook returned %s", resp.Status)
+}
+
+// signPayload returns the SignatureHeader value of body
+func signPayload(key, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,137 @@
+package hooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+// testWebhook returns a webhook backend for url with short timings
+func testWebhook(url, key string) *webhookBackend {
+	b := newWebhookBackend(url, key)
+	b.attemptTimeout = 200 * time.Millisecond
+	b.backoff = 10 * time.Millisecond
+	return b
+}
+
+func TestWebhookDelivers(t *testing.T) {
+	const key = "s3cret"
+	n := &Notification{
+		Session:   "s",
+		Title:     "My task",
+		RepoURL:   "https://github.com/org/repo",
+		OldState:  types.ClaudeStateRunning,
+		NewState:  types.ClaudeStateWaitingForInput,
+		Timestamp: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	var got Notification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write(body)
+		want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		if r.Header.Get(SignatureHeader) != want {
+			t.Errorf("signature = %q, want %q", r.Header.Get(SignatureHeader), want)
+		}
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request %s with content type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Errorf("body is not a notification: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	if err := testWebhook(server.URL, key).Notify(context.Background(), n); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+	if got != *n {
+		t.Errorf("received %+v, want %+v", got, *n)
+	}
+}
+
+func TestWebhookUnsigned(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if sig := r.Header.Get(SignatureHeader); sig != "" {
+			t.Errorf("expected no signature without a secret, got %q", sig)
+		}
+	}))
+	defer server.Close()
+
+	if err := testWebhook(server.URL, "").Notify(context.Background(), &Notification{Session: "s"}); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+}
+
+func TestWebhookRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		statuses  []int
+		wantCalls int32
+		wantErr   bool
+	}{
+		{name: "recovers after server errors", statuses: []int{500, 503, 200}, wantCalls: 3},
+		{name: "retries rate limiting", statuses: []int{429, 200}, wantCalls: 2},
+		{name: "gives up after all attempts", statuses: []int{500, 500, 500, 200}, wantCalls: 3, wantErr: true},
+		{name: "does not retry client errors", statuses: []int{400, 200}, wantCalls: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				i := calls.Add(1) - 1
+				w.WriteHeader(tt.statuses[i])
+			}))
+			defer server.Close()
+
+			err := testWebhook(server.URL, "").Notify(context.Background(), &Notification{Session: "s"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Notify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls.Load() != tt.wantCalls {
+				t.Errorf("got %d requests, want %d", calls.Load(), tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestWebhookBounded(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	b := testWebhook(server.URL, "")
+	b.attemptTimeout = time.Second
+	b.backoff = time.Second
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := b.Notify(ctx, &Notification{Session: "s"}); err == nil {
+		t.Fatal("expected a hanging receiver to fail")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Notify took %v, want it cut off by the deadline", elapsed)
+	}
+}
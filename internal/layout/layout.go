@@ -0,0 +1,232 @@
+package layout
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ClaudeWindowName is the name of the window running Claude. State detection
+// and the status hooks look the window up by this name.
+const ClaudeWindowName = "claude"
+
+// File is the top-level structure of layouts.yaml
+type File struct {
+	Layouts     map[string]*Layout `yaml:"layouts"`
+	Assignments map[string]string  `yaml:"assignments"` // repo (org/repo) or workspace name -> layout
+	Default     string             `yaml:"default"`
+}
+
+// Layout describes the windows of a session
+type Layout struct {
+	Windows []*Window `yaml:"windows"`
+}
+
+// Window is a single tmux window with one or more panes
+type Window struct {
+	Name   string  `yaml:"name"`
+	Dir    string  `yaml:"dir"`    // Working directory relative to the session root
+	Layout string  `yaml:"layout"` // tmux layout applied after all panes exist
+	Panes  []*Pane `yaml:"panes"`
+}
+
+// Pane is a single pane within a window
+type Pane struct {
+	Command string `yaml:"command"` // Empty starts a shell
+	Dir     string `yaml:"dir"`     // Overrides the window's Dir
+	Split   string `yaml:"split"`   // "horizontal" (side by side) or "vertical" (default)
+	Claude  bool   `yaml:"claude"`  // Run Claude in this pane
+}
+
+// Builder is the subset of tmux operations needed to build a layout
+type Builder interface {
+	CreateSessionWithWindow(name, path, windowName, command string) (string, error)
+	CreateWindow(session, name, command, path string) (string, error)
+	SplitWindow(target, command, path string, horizontal bool) error
+	SelectLayout(target, layout string) error
+	SelectWindow(session, window string) error
+}
+
+// Load reads and validates a layouts file. A missing file yields an empty
+// File so sessions fall back to the single Claude window.
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &File{}, nil
+		}
+		return nil, fmt.Errorf("failed to read layouts file: %w", err)
+	}
+
+	return Parse(data)
+}
+
+// Parse parses and validates layouts YAML
+func Parse(data []byte) (*File, error) {
+	var f File
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse layouts file: %w", err)
+	}
+
+	for name, l := range f.Layouts {
+		if err := l.normalize(); err != nil {
+			return nil, fmt.Errorf("layout %q: %w", name, err)
+		}
+	}
+	for target, name := range f.Assignments {
+		if _, ok := f.Layouts[name]; !ok {
+			return nil, fmt.Errorf("assignment %q: unknown layout %q", target, name)
+		}
+	}
+	if _, ok := f.Layouts[f.Default]; f.Default != "" && !ok {
+		return nil, fmt.Errorf("unknown default layout %q", f.Default)
+	}
+
+	return &f, nil
+}
+
+// Get returns the layout with the given name
+func (f *File) Get(name string) (*Layout, error) {
+	l, ok := f.Layouts[name]
+	if !ok {
+		return nil, fmt.Errorf("layout %q not found", name)
+	}
+	return l, nil
+}
+
+// Resolve returns the name of the layout for a session: the explicitly
+// requested one, then the one assigned to target (org/repo or workspace
+// name), then the default. Returns an empty name when none applies.
+func (f *File) Resolve(requested, target string) (string, error) {
+	if requested != "" {
+		if _, err := f.Get(requested); err != nil {
+			return "", err
+		}
+		return requested, nil
+	}
+	if name, ok := f.Assignments[target]; ok {
+		return name, nil
+	}
+	return f.Default, nil
+}
+
+// normalize validates the layout and fills in defaults: an empty window gets
+// a single shell pane, the first pane runs Claude unless another one is
+// marked, and the window running Claude is named ClaudeWindowName.
+func (l *Layout) normalize() error {
+	if len(l.Windows) == 0 {
+		return fmt.Errorf("no windows defined")
+	}
+
+	claudePanes := 0
+	for _, w := range l.Windows {
+		if len(w.Panes) == 0 {
+			w.Panes = []*Pane{{}}
+		}
+		for _, p := range w.Panes {
+			if p.Claude {
+				claudePanes++
+			}
+			switch p.Split {
+			case "", "vertical", "horizontal":
+			default:
+				return fmt.Errorf("invalid split %q, want horizontal or vertical", p.Split)
+			}
+		}
+	}
+
+	switch claudePanes {
+	case 0:
+		l.Windows[0].Panes[0].Claude = true
+	case 1:
+	default:
+		return fmt.Errorf("only one pane can run claude, found %d", claudePanes)
+	}
+
+	for _, w := range l.Windows {
+		if !w.runsClaude() {
+			continue
+		}
+		if w.Name == "" {
+			w.Name = ClaudeWindowName
+		}
+		if w.Name != ClaudeWindowName {
+			return fmt.Errorf("window running claude must be named %q, got %q", ClaudeWindowName, w.Name)
+		}
+	}
+
+	return nil
+}
+
+// runsClaude reports whether one of the window's panes runs Claude
+func (w *Window) runsClaude() bool {
+	for _, p := range w.Panes {
+		if p.Claude {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply creates the tmux session sessionName rooted at rootPath with all
+// windows and panes of the layout. claudeCmd runs in the Claude pane; an
+// empty claudeCmd leaves that pane with a shell.
+func Apply(b Builder, sessionName, rootPath, claudeCmd string, l *Layout) error {
+	var firstWindow string
+
+	for i, w := range l.Windows {
+		var windowID string
+		var err error
+
+		first := w.Panes[0]
+		command := paneCommand(first, claudeCmd)
+		if i == 0 {
+			windowID, err = b.CreateSessionWithWindow(sessionName, paneDir(rootPath, w, first), w.Name, command)
+			if err != nil {
+				return fmt.Errorf("failed to create session: %w", err)
+			}
+			firstWindow = windowID
+		} else {
+			windowID, err = b.CreateWindow(sessionName, w.Name, command, paneDir(rootPath, w, first))
+			if err != nil {
+				return fmt.Errorf("failed to create window %q: %w", w.Name, err)
+			}
+		}
+
+		for _, p := range w.Panes[1:] {
+			if err := b.SplitWindow(windowID, paneCommand(p, claudeCmd), paneDir(rootPath, w, p), p.Split == "horizontal"); err != nil {
+				return fmt.Errorf("failed to split window %q: %w", w.Name, err)
+			}
+		}
+
+		if w.Layout != "" {
+			if err := b.SelectLayout(windowID, w.Layout); err != nil {
+				return fmt.Errorf("failed to apply layout %q to window %q: %w", w.Layout, w.Name, err)
+			}
+		}
+	}
+
+	return b.SelectWindow(sessionName, firstWindow)
+}
+
+// paneCommand returns the command a pane runs
+func paneCommand(p *Pane, claudeCmd string) string {
+	if p.Claude {
+		return claudeCmd
+	}
+	return p.Command
+}
+
+// paneDir returns the absolute working directory of a pane
+func paneDir(rootPath string, w *Window, p *Pane) string {
+	dir := w.Dir
+	if p.Dir != "" {
+		dir = p.Dir
+	}
+	if dir == "" {
+		return rootPath
+	}
+	return filepath.Join(rootPath, dir)
+}
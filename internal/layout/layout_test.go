@@ -0,0 +1,262 @@
+package layout
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sampleLayouts = `layouts:
+  dev:
+    windows:
+      - name: claude
+        layout: main-vertical
+        panes:
+          - claude: true
+          - split: horizontal
+      - name: tests
+        dir: backend
+        panes:
+          - command: make test --watch
+  minimal:
+    windows:
+      - panes:
+          - command: ""
+assignments:
+  org/api: dev
+  fullstack: dev
+default: minimal
+`
+
+func TestParse(t *testing.T) {
+	f, err := Parse([]byte(sampleLayouts))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	dev, err := f.Get("dev")
+	if err != nil {
+		t.Fatalf("Get(dev) failed: %v", err)
+	}
+	if len(dev.Windows) != 2 {
+		t.Fatalf("expected 2 windows, got %d", len(dev.Windows))
+	}
+	if len(dev.Windows[0].Panes) != 2 {
+		t.Errorf("expected 2 panes in claude window, got %d", len(dev.Windows[0].Panes))
+	}
+
+	// First pane runs claude by default and its window gets the claude name
+	minimal, err := f.Get("minimal")
+	if err != nil {
+		t.Fatalf("Get(minimal) failed: %v", err)
+	}
+	if !minimal.Windows[0].Panes[0].Claude {
+		t.Error("first pane should run claude when no pane is marked")
+	}
+	if minimal.Windows[0].Name != ClaudeWindowName {
+		t.Errorf("claude window name = %q, want %q", minimal.Windows[0].Name, ClaudeWindowName)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "no windows",
+			content: "layouts:\n  empty: {}\n",
+			wantErr: "no windows",
+		},
+		{
+			name: "two claude panes",
+			content: `layouts:
+  bad:
+    windows:
+      - panes:
+          - claude: true
+          - claude: true
+`,
+			wantErr: "only one pane",
+		},
+		{
+			name: "claude window with another name",
+			content: `layouts:
+  bad:
+    windows:
+      - name: agent
+        panes:
+          - claude: true
+`,
+			wantErr: "must be named",
+		},
+		{
+			name: "invalid split",
+			content: `layouts:
+  bad:
+    windows:
+      - panes:
+          - claude: true
+          - split: diagonal
+`,
+			wantErr: "invalid split",
+		},
+		{
+			name: "unknown assignment",
+			content: `layouts:
+  dev:
+    windows:
+      - name: claude
+assignments:
+  org/api: missing
+`,
+			wantErr: "unknown layout",
+		},
+		{
+			name: "unknown default",
+			content: `layouts:
+  dev:
+    windows:
+      - name: claude
+default: missing
+`,
+			wantErr: "unknown default",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	f, err := Load(filepath.Join(t.TempDir(), "layouts.yaml"))
+	if err != nil {
+		t.Fatalf("Load of missing file should not fail: %v", err)
+	}
+	name, err := f.Resolve("", "org/api")
+	if err != nil || name != "" {
+		t.Errorf("Resolve() = %q, %v; want no layout", name, err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	f, err := Parse([]byte(sampleLayouts))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		requested string
+		target    string
+		want      string
+		wantErr   bool
+	}{
+		{name: "requested wins", requested: "minimal", target: "org/api", want: "minimal"},
+		{name: "assignment by repo", target: "org/api", want: "dev"},
+		{name: "assignment by workspace", target: "fullstack", want: "dev"},
+		{name: "default", target: "org/other", want: "minimal"},
+		{name: "unknown requested", requested: "missing", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.Resolve(tt.requested, tt.target)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Resolve() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeBuilder records tmux operations instead of running them
+type fakeBuilder struct {
+	calls   []string
+	windows int
+}
+
+func (b *fakeBuilder) nextID() string {
+	b.windows++
+	return fmt.Sprintf("@%d", b.windows)
+}
+
+func (b *fakeBuilder) CreateSessionWithWindow(name, path, windowName, command string) (string, error) {
+	id := b.nextID()
+	b.calls = append(b.calls, fmt.Sprintf("new-session %s %s %s %q -> %s", name, path, windowName, command, id))
+	return id, nil
+}
+
+func (b *fakeBuilder) CreateWindow(session, name, command, path string) (string, error) {
+	id := b.nextID()
+	b.calls = append(b.calls, fmt.Sprintf("new-window %s %s %q %s -> %s", session, name, command, path, id))
+	return id, nil
+}
+
+func (b *fakeBuilder) SplitWindow(target, command, path string, horizontal bool) error {
+	b.calls = append(b.calls, fmt.Sprintf("split-window %s %q %s %v", target, command, path, horizontal))
+	return nil
+}
+
+func (b *fakeBuilder) SelectLayout(target, layout string) error {
+	b.calls = append(b.calls, fmt.Sprintf("select-layout %s %s", target, layout))
+	return nil
+}
+
+func (b *fakeBuilder) SelectWindow(session, window string) error {
+	b.calls = append(b.calls, fmt.Sprintf("select-window %s %s", session, window))
+	return nil
+}
+
+func TestApply(t *testing.T) {
+	f, err := Parse([]byte(sampleLayouts))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	dev, err := f.Get("dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := &fakeBuilder{}
+	if err := Apply(b, "org-api", "/repos/org-api", "claude --continue", dev); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	want := []string{
+		`new-session org-api /repos/org-api claude "claude --continue" -> @1`,
+		`split-window @1 "" /repos/org-api true`,
+		`select-layout @1 main-vertical`,
+		`new-window org-api tests "make test --watch" /repos/org-api/backend -> @2`,
+		`select-window org-api @1`,
+	}
+	if len(b.calls) != len(want) {
+		t.Fatalf("got %d calls, want %d:\n%s", len(b.calls), len(want), strings.Join(b.calls, "\n"))
+	}
+	for i := range want {
+		if b.calls[i] != want[i] {
+			t.Errorf("call %d = %s, want %s", i, b.calls[i], want[i])
+		}
+	}
+}
+
+func TestExampleLayoutsFileParses(t *testing.T) {
+	data, err := os.ReadFile("../../config/layouts.example.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Parse(data); err != nil {
+		t.Errorf("example layouts file does not parse: %v", err)
+	}
+}
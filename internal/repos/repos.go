@@ -35,9 +35,14 @@ func (d *Discoverer) ListAll(ctx context.Context) ([]*types.Repository, error) {
 		}
 
 		for _, repo := range repos {
-			if !seen[repo.URL] {
+			// Workspaces have no URL, so they are keyed by name instead
+			key := repo.URL
+			if repo.IsWorkspace {
+				key = "workspace:" + repo.Name
+			}
+			if !seen[key] {
 				allRepos = append(allRepos, repo)
-				seen[repo.URL] = true
+				seen[key] = true
 			}
 		}
 	}
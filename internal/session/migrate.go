@@ -0,0 +1,169 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+// CurrentSchemaVersion is the session file format written by Save. Files
+// without a schema_version field are version 0.
+const CurrentSchemaVersion = 1
+
+// migration upgrades a raw session file from version `from` to from+1 and
+// describes each change it made. Migrations work on the raw JSON object so
+// they keep working after types.Session changes shape.
+type migration struct {
+	from  int
+	apply func(raw map[string]any, file fileInfo) []string
+}
+
+// fileInfo describes the session file being migrated
+type fileInfo struct {
+	name    string // Session name derived from the file name
+	modTime time.Time
+}
+
+// migrations is the upgrade chain, ordered by version
+var migrations = []migration{
+	{from: 0, apply: migrateUnversioned},
+}
+
+// MigrationResult describes the upgrade of one session file
+type MigrationResult struct {
+	Name    string
+	From    int
+	To      int
+	Changes []string
+}
+
+// migrateUnversioned fills in fields that sessions written before the schema
+// was versioned could lack
+func migrateUnversioned(raw map[string]any, file fileInfo) []string {
+	var changes []string
+
+	if name, _ := raw["name"].(string); name == "" {
+		raw["name"] = file.name
+		changes = append(changes, fmt.Sprintf("set missing name to %q", file.name))
+	}
+
+	createdAt, _ := raw["created_at"].(string)
+	if t, err := time.Parse(time.RFC3339Nano, createdAt); err != nil || t.IsZero() {
+		raw["created_at"] = file.modTime.UTC().Format(time.RFC3339Nano)
+		changes = append(changes, "set missing created_at from the file's modification time")
+	}
+
+	return changes
+}
+
+// upgrade runs the migrations needed to bring raw to CurrentSchemaVersion
+func upgrade(raw map[string]any, file fileInfo) (*MigrationResult, error) {
+	version := 0
+	if v, ok := raw["schema_version"].(float64); ok {
+		version = int(v)
+	}
+	if version > CurrentSchemaVersion {
+		return nil, fmt.Errorf("schema version %d is newer than supported version %d", version, CurrentSchemaVersion)
+	}
+
+	result := &MigrationResult{Name: file.name, From: version, To: version}
+	for _, mig := range migrations {
+		if mig.from != result.To {
+			continue
+		}
+		result.Changes = append(result.Changes, mig.apply(raw, file)...)
+		result.To = mig.from + 1
+	}
+	raw["schema_version"] = result.To
+
+	return result, nil
+}
+
+// read loads and parses a session file, upgrading it in memory. The result
+// is nil when the file is already current.
+func (m *Manager) read(name string) (*types.Session, *MigrationResult, error) {
+	path := m.path(name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, nil, fmt.Errorf("corrupt session file %s: %w", path, err)
+	}
+
+	var result *MigrationResult
+	if v, _ := raw["schema_version"].(float64); int(v) != CurrentSchemaVersion {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		result, err = upgrade(raw, fileInfo{name: name, modTime: info.ModTime()})
+		if err != nil {
+			return nil, nil, fmt.Errorf("session file %s: %w", path, err)
+		}
+		if data, err = json.Marshal(raw); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var s types.Session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, nil, fmt.Errorf("corrupt session file %s: %w", path, err)
+	}
+
+	return &s, result, nil
+}
+
+// Migrate upgrades every session file to CurrentSchemaVersion and reports
+// the files that changed. With dryRun set nothing is written.
+func (m *Manager) Migrate(dryRun bool) ([]*MigrationResult, error) {
+	unlock, err := m.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	entries, err := os.ReadDir(m.metadataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*MigrationResult
+	var corrupt *CorruptError
+	for _, entry := range entries {
+		if filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), ".json")
+
+		s, result, err := m.read(name)
+		if err != nil {
+			if corrupt == nil {
+				corrupt = &CorruptError{Files: make(map[string]error)}
+			}
+			corrupt.Files[m.path(name)] = err
+			continue
+		}
+		if result == nil {
+			continue
+		}
+		if !dryRun {
+			if err := m.save(s); err != nil {
+				return results, fmt.Errorf("failed to write %s: %w", m.path(name), err)
+			}
+		}
+		results = append(results, result)
+	}
+
+	if corrupt != nil {
+		return results, corrupt
+	}
+	return results, nil
+}
@@ -0,0 +1,111 @@
+package session
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+// readRaw returns the top-level fields of a session file as stored on disk
+func readRaw(t *testing.T, path string) map[string]any {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func TestLoad_UpgradesInPlace(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "legacy.json")
+	legacy := `{"created_at": "0001-01-01T00:00:00Z", "repo_url": "https://github.com/org/repo", "clone_path": "/tmp/legacy"}`
+	if err := os.WriteFile(path, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	sess, err := NewManager(tmpDir).Load("legacy")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if sess.Name != "legacy" {
+		t.Errorf("Name = %q, want %q", sess.Name, "legacy")
+	}
+	if !sess.CreatedAt.Equal(mtime) {
+		t.Errorf("CreatedAt = %v, want %v", sess.CreatedAt, mtime)
+	}
+	if sess.SchemaVersion != CurrentSchemaVersion {
+		t.Errorf("SchemaVersion = %d, want %d", sess.SchemaVersion, CurrentSchemaVersion)
+	}
+
+	raw := readRaw(t, path)
+	if raw["schema_version"] != float64(CurrentSchemaVersion) {
+		t.Errorf("file schema_version = %v, want %d", raw["schema_version"], CurrentSchemaVersion)
+	}
+	if raw["clone_path"] != "/tmp/legacy" {
+		t.Errorf("file clone_path = %v, want it preserved", raw["clone_path"])
+	}
+}
+
+func TestLoad_NewerSchemaVersion(t *testing.T) {
+	tmpDir := t.TempDir()
+	future := `{"schema_version": 99, "name": "future"}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "future.json"), []byte(future), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewManager(tmpDir).Load("future"); err == nil {
+		t.Error("Load should refuse a file from a newer version")
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	tmpDir := t.TempDir()
+	mgr := NewManager(tmpDir)
+
+	if err := mgr.Save(&types.Session{Name: "current", CreatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	legacyPath := filepath.Join(tmpDir, "legacy.json")
+	if err := os.WriteFile(legacyPath, []byte(`{"name": "legacy"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := mgr.Migrate(true)
+	if err != nil {
+		t.Fatalf("Migrate(dry run) failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Name != "legacy" || results[0].From != 0 || results[0].To != CurrentSchemaVersion {
+		t.Fatalf("Migrate(dry run) = %+v, want one upgrade of legacy from 0", results)
+	}
+	if len(results[0].Changes) != 1 {
+		t.Errorf("Changes = %v, want only created_at filled in", results[0].Changes)
+	}
+	if _, ok := readRaw(t, legacyPath)["schema_version"]; ok {
+		t.Error("dry run must not write the file")
+	}
+
+	if _, err := mgr.Migrate(false); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	if readRaw(t, legacyPath)["schema_version"] != float64(CurrentSchemaVersion) {
+		t.Error("Migrate should write the upgraded file")
+	}
+
+	results, err = mgr.Migrate(false)
+	if err != nil || len(results) != 0 {
+		t.Errorf("second Migrate = (%+v, %v), want nothing to do", results, err)
+	}
+}
@@ -0,0 +1,46 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/mateimicu/tmux-claude-matrix/internal/atomicfile"
+)
+
+// NotesPath returns the path of the free-form notes file of a session. It
+// lives next to the metadata and may not exist yet.
+func (m *Manager) NotesPath(name string) string {
+	return filepath.Join(m.metadataDir, name+".notes.md")
+}
+
+// LoadNotes returns the notes of a session, or an empty string if it has none
+func (m *Manager) LoadNotes(name string) (string, error) {
+	data, err := os.ReadFile(m.NotesPath(name))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	return string(data), err
+}
+
+// SaveNotes replaces the notes of a session. Empty notes remove the file.
+func (m *Manager) SaveNotes(name, notes string) error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if notes == "" {
+		return m.removeNotes(name)
+	}
+	return atomicfile.Write(m.NotesPath(name), []byte(notes))
+}
+
+// removeNotes deletes the notes file of a session if there is one. Callers
+// hold the lock.
+func (m *Manager) removeNotes(name string) error {
+	if err := os.Remove(m.NotesPath(name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
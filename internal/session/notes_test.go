@@ -0,0 +1,57 @@
+package session
+
+import (
+	"os"
+	"testing"
+
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+func TestNotes(t *testing.T) {
+	mgr := NewManager(t.TempDir())
+	if err := mgr.Save(&types.Session{Name: "s1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	notes, err := mgr.LoadNotes("s1")
+	if err != nil || notes != "" {
+		t.Fatalf("LoadNotes() = %q, %v; want no notes", notes, err)
+	}
+
+	if err := mgr.SaveNotes("s1", "next: fix the flaky test\n"); err != nil {
+		t.Fatalf("SaveNotes() error = %v", err)
+	}
+	if notes, _ := mgr.LoadNotes("s1"); notes != "next: fix the flaky test\n" {
+		t.Errorf("LoadNotes() = %q", notes)
+	}
+
+	// Notes files must not show up as sessions
+	sessions, err := mgr.List()
+	if err != nil || len(sessions) != 1 {
+		t.Errorf("List() = %d sessions, %v; want 1", len(sessions), err)
+	}
+
+	if err := mgr.SaveNotes("s1", ""); err != nil {
+		t.Fatalf("SaveNotes(\"\") error = %v", err)
+	}
+	if _, err := os.Stat(mgr.NotesPath("s1")); !os.IsNotExist(err) {
+		t.Error("empty notes should remove the notes file")
+	}
+}
+
+func TestDelete_RemovesNotes(t *testing.T) {
+	mgr := NewManager(t.TempDir())
+	if err := mgr.Save(&types.Session{Name: "s1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.SaveNotes("s1", "notes"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mgr.Delete("s1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := os.Stat(mgr.NotesPath("s1")); !os.IsNotExist(err) {
+		t.Error("Delete() should remove the notes file")
+	}
+}
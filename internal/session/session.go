@@ -6,12 +6,47 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
+	"github.com/mateimicu/tmux-claude-matrix/internal/atomicfile"
 	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
 )
 
-// Manager manages session metadata
+// lockFileName is the advisory lock guarding writes to the metadata dir
+const lockFileName = ".lock"
+
+// ReservationTTL is how long a name reserved by GenerateUniqueName or Reserve
+// stays taken without being saved. It outlives any clone so only
+// reservations of crashed creates expire.
+const ReservationTTL = time.Hour
+
+// CorruptError reports metadata files that could not be read. List returns it
+// together with all sessions that could be read.
+type CorruptError struct {
+	Files map[string]error // Path -> parse error
+}
+
+func (e *CorruptError) Error() string {
+	paths := make([]string, 0, len(e.Files))
+	for path := range e.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	msgs := make([]string, 0, len(paths))
+	for _, path := range paths {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", path, e.Files[path]))
+	}
+	return fmt.Sprintf("%d corrupt session file(s): %s", len(paths), strings.Join(msgs, "; "))
+}
+
+// Manager manages session metadata. Writes are serialized across processes
+// with an advisory lock and replace files atomically, so readers never see
+// a partially written session.
 type Manager struct {
 	metadataDir string
 }
@@ -21,38 +56,62 @@ func NewManager(metadataDir string) *Manager {
 	return &Manager{metadataDir: metadataDir}
 }
 
-// Save writes session metadata to disk
+// Save writes session metadata to disk, releasing any reservation of its name
 func (m *Manager) Save(s *types.Session) error {
-	if err := os.MkdirAll(m.metadataDir, 0755); err != nil {
+	unlock, err := m.lock()
+	if err != nil {
 		return err
 	}
+	defer unlock()
 
-	path := filepath.Join(m.metadataDir, s.Name+".json")
-	data, err := json.MarshalIndent(s, "", "  ")
+	return m.save(s)
+}
+
+// Update loads a session, applies fn and saves the result while holding the
+// lock, so concurrent updates of different fields don't overwrite each other
+func (m *Manager) Update(name string, fn func(*types.Session) error) error {
+	unlock, err := m.lock()
 	if err != nil {
 		return err
 	}
+	defer unlock()
 
-	return os.WriteFile(path, data, 0644)
+	s, _, err := m.read(name)
+	if err != nil {
+		return err
+	}
+	if err := fn(s); err != nil {
+		return err
+	}
+	return m.save(s)
 }
 
-// Load reads session metadata from disk
+// Load reads session metadata from disk. Files in an older format are
+// upgraded in place.
 func (m *Manager) Load(name string) (*types.Session, error) {
-	path := filepath.Join(m.metadataDir, name+".json")
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
+	s, result, err := m.read(name)
+	if err != nil || result == nil {
+		return s, err
 	}
 
-	var s types.Session
-	if err := json.Unmarshal(data, &s); err != nil {
-		return nil, err
+	// Re-read under the lock so a concurrent write isn't overwritten
+	unlock, err := m.lock()
+	if err != nil {
+		return s, nil
 	}
+	defer unlock()
 
-	return &s, nil
+	if current, result, err := m.read(name); err == nil {
+		s = current
+		if result != nil {
+			m.save(s) //nolint:errcheck // The upgraded session is usable even if it can't be written back
+		}
+	}
+	return s, nil
 }
 
-// List returns all sessions
+// List returns all sessions. Files that can't be parsed are reported with a
+// *CorruptError; the sessions that could be read are returned alongside it.
 func (m *Manager) List() ([]*types.Session, error) {
 	entries, err := os.ReadDir(m.metadataDir)
 	if err != nil {
@@ -63,39 +122,71 @@ func (m *Manager) List() ([]*types.Session, error) {
 	}
 
 	var sessions []*types.Session
+	var corrupt *CorruptError
 	for _, entry := range entries {
-		if filepath.Ext(entry.Name()) == ".json" {
-			name := strings.TrimSuffix(entry.Name(), ".json")
-			if s, err := m.Load(name); err == nil {
-				sessions = append(sessions, s)
+		if filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		s, err := m.Load(name)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// Deleted since ReadDir
+				continue
+			}
+			if corrupt == nil {
+				corrupt = &CorruptError{Files: make(map[string]error)}
 			}
+			corrupt.Files[m.path(name)] = err
+			continue
 		}
+		sessions = append(sessions, s)
 	}
 
+	if corrupt != nil {
+		return sessions, corrupt
+	}
 	return sessions, nil
 }
 
-// Delete removes session metadata
+// Delete removes session metadata and notes
 func (m *Manager) Delete(name string) error {
-	path := filepath.Join(m.metadataDir, name+".json")
-	return os.Remove(path)
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := os.Remove(m.path(name)); err != nil {
+		return err
+	}
+	return m.removeNotes(name)
 }
 
-// Exists checks if a session exists
+// Exists checks if a session exists or its name is reserved
 func (m *Manager) Exists(name string) bool {
-	path := filepath.Join(m.metadataDir, name+".json")
-	_, err := os.Stat(path)
-	return err == nil
+	if _, err := os.Stat(m.path(name)); err == nil {
+		return true
+	}
+	return m.reserved(name)
 }
 
-// GenerateUniqueName creates a unique session name
+// GenerateUniqueName creates a unique session name and reserves it until the
+// session is saved or Release is called, so concurrent creates never pick
+// the same name
 func (m *Manager) GenerateUniqueName(base string) (string, error) {
+	unlock, err := m.lock()
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
 	name := sanitizeName(base)
 	counter := 1
 
 	for {
 		if !m.Exists(name) {
-			return name, nil
+			return name, m.reserve(name)
 		}
 		name = fmt.Sprintf("%s-%d", sanitizeName(base), counter)
 		counter++
@@ -106,6 +197,80 @@ func (m *Manager) GenerateUniqueName(base string) (string, error) {
 	}
 }
 
+// Reserve claims an exact session name until the session is saved or
+// Release is called. It fails if the name is taken.
+func (m *Manager) Reserve(name string) error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if m.Exists(name) {
+		return fmt.Errorf("session %q already exists", name)
+	}
+	return m.reserve(name)
+}
+
+// Release drops the reservation of a name that was never saved. It is a
+// no-op once the session has been saved.
+func (m *Manager) Release(name string) {
+	os.Remove(m.reservationPath(name)) //nolint:errcheck // Missing reservation is fine
+}
+
+func (m *Manager) path(name string) string {
+	return filepath.Join(m.metadataDir, name+".json")
+}
+
+func (m *Manager) reservationPath(name string) string {
+	return filepath.Join(m.metadataDir, name+".reserved")
+}
+
+// reserved reports whether name has a reservation that hasn't expired
+func (m *Manager) reserved(name string) bool {
+	info, err := os.Stat(m.reservationPath(name))
+	return err == nil && time.Since(info.ModTime()) < ReservationTTL
+}
+
+// reserve writes the reservation file of name. Callers hold the lock.
+func (m *Manager) reserve(name string) error {
+	return os.WriteFile(m.reservationPath(name), []byte(strconv.Itoa(os.Getpid())), 0644)
+}
+
+// save atomically writes s and drops its reservation. Callers hold the lock.
+func (m *Manager) save(s *types.Session) error {
+	s.SchemaVersion = CurrentSchemaVersion
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := atomicfile.Write(m.path(s.Name), data); err != nil {
+		return err
+	}
+	m.Release(s.Name)
+	return nil
+}
+
+// lock takes the exclusive advisory lock of the metadata dir, creating the
+// dir if needed, and returns the function releasing it
+func (m *Manager) lock() (func(), error) {
+	if err := os.MkdirAll(m.metadataDir, 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(filepath.Join(m.metadataDir, lockFileName), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open session lock: %w", err)
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close() //nolint:errcheck // Already failing
+		return nil, fmt.Errorf("failed to lock sessions dir: %w", err)
+	}
+	return func() {
+		syscall.Flock(int(f.Fd()), syscall.LOCK_UN) //nolint:errcheck // Closing releases the lock too
+		f.Close()                                   //nolint:errcheck // Nothing left to flush
+	}, nil
+}
+
 // sanitizeName converts a string to a valid tmux session name
 func sanitizeName(s string) string {
 	// Remove special characters, keep alphanumeric, dash, underscore
@@ -1,8 +1,10 @@
 package session
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -245,3 +247,171 @@ func TestSanitizeName(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateUniqueName_Concurrent(t *testing.T) {
+	mgr := NewManager(t.TempDir())
+
+	const n = 20
+	names := make(chan string, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Each goroutine gets its own manager, as separate processes would
+			name, err := NewManager(mgr.metadataDir).GenerateUniqueName("org/repo")
+			if err != nil {
+				t.Errorf("GenerateUniqueName failed: %v", err)
+				return
+			}
+			names <- name
+		}()
+	}
+	wg.Wait()
+	close(names)
+
+	seen := make(map[string]bool)
+	for name := range names {
+		if seen[name] {
+			t.Errorf("name %q handed out twice", name)
+		}
+		seen[name] = true
+	}
+	if len(seen) != n {
+		t.Errorf("got %d unique names, want %d", len(seen), n)
+	}
+}
+
+func TestReserveAndRelease(t *testing.T) {
+	mgr := NewManager(t.TempDir())
+
+	if err := mgr.Reserve("s1"); err != nil {
+		t.Fatalf("Reserve failed: %v", err)
+	}
+	if !mgr.Exists("s1") {
+		t.Error("reserved name should exist")
+	}
+	if err := mgr.Reserve("s1"); err == nil {
+		t.Error("Reserve should fail for a reserved name")
+	}
+
+	// Reservations don't show up as sessions
+	sessions, err := mgr.List()
+	if err != nil || len(sessions) != 0 {
+		t.Errorf("List() = (%v, %v), want no sessions", sessions, err)
+	}
+
+	mgr.Release("s1")
+	if mgr.Exists("s1") {
+		t.Error("released name should not exist")
+	}
+
+	// Saving drops the reservation
+	if err := mgr.Reserve("s2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.Save(&types.Session{Name: "s2"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(mgr.reservationPath("s2")); !os.IsNotExist(err) {
+		t.Error("Save should remove the reservation")
+	}
+
+	// Reservations of crashed creates expire
+	if err := mgr.Reserve("s3"); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * ReservationTTL)
+	if err := os.Chtimes(mgr.reservationPath("s3"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	if mgr.Exists("s3") {
+		t.Error("expired reservation should not count")
+	}
+}
+
+func TestList_ReportsCorruptFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	mgr := NewManager(tmpDir)
+
+	if err := mgr.Save(&types.Session{Name: "good"}); err != nil {
+		t.Fatal(err)
+	}
+	truncated := filepath.Join(tmpDir, "bad.json")
+	if err := os.WriteFile(truncated, []byte(`{"name": "ba`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions, err := mgr.List()
+	var corrupt *CorruptError
+	if !errors.As(err, &corrupt) {
+		t.Fatalf("List() error = %v, want *CorruptError", err)
+	}
+	if _, ok := corrupt.Files[truncated]; !ok || len(corrupt.Files) != 1 {
+		t.Errorf("CorruptError.Files = %v, want only %s", corrupt.Files, truncated)
+	}
+	if len(sessions) != 1 || sessions[0].Name != "good" {
+		t.Errorf("List() sessions = %v, want only good", sessions)
+	}
+}
+
+func TestSave_LeavesNoTempFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	mgr := NewManager(tmpDir)
+
+	for i := 0; i < 3; i++ {
+		if err := mgr.Save(&types.Session{Name: "s1", Title: "t"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tmps, err := filepath.Glob(filepath.Join(tmpDir, "*.tmp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tmps) != 0 {
+		t.Errorf("temp files left behind: %v", tmps)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	mgr := NewManager(t.TempDir())
+	if err := mgr.Save(&types.Session{Name: "s1", Title: "old"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Concurrent updates of different fields must both survive
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		if err := NewManager(mgr.metadataDir).Update("s1", func(s *types.Session) error {
+			s.Title = "new"
+			return nil
+		}); err != nil {
+			t.Errorf("Update failed: %v", err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		if err := NewManager(mgr.metadataDir).Update("s1", func(s *types.Session) error {
+			s.ClaudeSessionID = "sess-1"
+			return nil
+		}); err != nil {
+			t.Errorf("Update failed: %v", err)
+		}
+	}()
+	wg.Wait()
+
+	s, err := mgr.Load("s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Title != "new" || s.ClaudeSessionID != "sess-1" {
+		t.Errorf("got Title=%q ClaudeSessionID=%q, want both updates", s.Title, s.ClaudeSessionID)
+	}
+
+	if err := mgr.Update("missing", func(*types.Session) error { return nil }); err == nil {
+		t.Error("Update of a missing session should fail")
+	}
+}
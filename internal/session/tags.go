@@ -0,0 +1,57 @@
+package session
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// ParseTags splits tag arguments on commas and whitespace, strips a leading
+// '#', and returns the distinct tags sorted
+func ParseTags(args ...string) []string {
+	var tags []string
+	for _, arg := range args {
+		fields := strings.FieldsFunc(arg, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+		tags = append(tags, fields...)
+	}
+	return normalizeTags(tags)
+}
+
+// AddTags returns tags with extra added
+func AddTags(tags []string, extra ...string) []string {
+	return normalizeTags(append(append([]string(nil), tags...), extra...))
+}
+
+// RemoveTags returns tags without any of remove
+func RemoveTags(tags []string, remove ...string) []string {
+	drop := make(map[string]bool, len(remove))
+	for _, t := range normalizeTags(remove) {
+		drop[t] = true
+	}
+	var kept []string
+	for _, t := range tags {
+		if !drop[t] {
+			kept = append(kept, t)
+		}
+	}
+	return normalizeTags(kept)
+}
+
+// normalizeTags strips a leading '#', drops empty and duplicate tags and
+// sorts the rest
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	var out []string
+	for _, t := range tags {
+		t = strings.TrimLeft(strings.TrimSpace(t), "#")
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	sort.Strings(out)
+	return out
+}
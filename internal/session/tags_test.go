@@ -0,0 +1,44 @@
+package session
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{name: "none", args: nil, want: nil},
+		{name: "separate args", args: []string{"backend", "urgent"}, want: []string{"backend", "urgent"}},
+		{name: "comma and space separated", args: []string{"urgent, backend  infra"}, want: []string{"backend", "infra", "urgent"}},
+		{name: "hash prefix and duplicates", args: []string{"#backend", "backend,#"}, want: []string{"backend"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseTags(tt.args...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTags(%q) = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddRemoveTags(t *testing.T) {
+	tags := []string{"backend", "urgent"}
+
+	if got := AddTags(tags, "infra", "backend"); !reflect.DeepEqual(got, []string{"backend", "infra", "urgent"}) {
+		t.Errorf("AddTags() = %q", got)
+	}
+	if got := RemoveTags(tags, "#urgent", "missing"); !reflect.DeepEqual(got, []string{"backend"}) {
+		t.Errorf("RemoveTags() = %q", got)
+	}
+	if got := RemoveTags(tags, "backend", "urgent"); got != nil {
+		t.Errorf("RemoveTags() of all tags = %q, want nil", got)
+	}
+	if !reflect.DeepEqual(tags, []string{"backend", "urgent"}) {
+		t.Errorf("input modified: %q", tags)
+	}
+}
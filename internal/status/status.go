@@ -5,8 +5,10 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/mateimicu/tmux-claude-matrix/internal/atomicfile"
 	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
 )
 
@@ -15,6 +17,8 @@ type StateFile struct {
 	State     string    `json:"state"`
 	UpdatedAt time.Time `json:"updated_at"`
 	SessionID string    `json:"session_id,omitempty"`
+	// Since is when the aggregate entered State; agent files don't set it
+	Since time.Time `json:"since,omitempty"`
 }
 
 // DefaultStatusDir returns the default directory for state files.
@@ -25,15 +29,22 @@ func DefaultStatusDir() string {
 // DefaultStaleThreshold is the duration after which an agent state file is considered stale.
 const DefaultStaleThreshold = 10 * time.Minute
 
-// WriteState atomically writes a state file for the given session.
-// It creates statusDir if it doesn't exist, writes to a temp file, then renames.
+// WriteState atomically writes a state file for the given session,
+// creating statusDir if it doesn't exist.
 func WriteState(statusDir, sessionName string, state types.ClaudeState, claudeSessionID string) error {
 	sf := StateFile{
 		State:     string(state),
 		UpdatedAt: time.Now(),
 		SessionID: claudeSessionID,
 	}
-	return atomicWriteJSON(statusDir, stateFilePath(statusDir, sessionName), sf)
+	if err := os.MkdirAll(statusDir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(sf)
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(stateFilePath(statusDir, sessionName), data)
 }
 
 // ReadState reads and parses the state file for the given session.
@@ -103,7 +114,14 @@ func WriteAgentState(statusDir, sessionName, agentSessionID string, state types.
 		UpdatedAt: time.Now(),
 		SessionID: agentSessionID,
 	}
-	return atomicWriteJSON(statusDir, agentStateFilePath(statusDir, sessionName, agentSessionID), sf)
+	if err := os.MkdirAll(statusDir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(sf)
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(agentStateFilePath(statusDir, sessionName, agentSessionID), data)
 }
 
 // ReadAgentState reads a per-agent state file.
@@ -134,6 +152,7 @@ func RemoveAllAgentStates(statusDir, sessionName string) error {
 
 // UpdateAggregate recomputes the aggregate state from all per-agent files,
 // cleans up stale agent files, and writes the aggregate {sessionName}.state file.
+// Since is carried over from the previous aggregate unless the state changed.
 // Returns the computed aggregate state.
 func UpdateAggregate(statusDir, sessionName string, staleThreshold time.Duration) (types.ClaudeState, error) {
 	files, err := listAgentStateFiles(statusDir, sessionName)
@@ -162,7 +181,95 @@ func UpdateAggregate(statusDir, sessionName string, staleThreshold time.Duration
 	if bestState == types.ClaudeStateStopped {
 		return bestState, RemoveState(statusDir, sessionName)
 	}
-	return bestState, WriteState(statusDir, sessionName, bestState, "")
+
+	now := time.Now()
+	sf := StateFile{State: string(bestState), UpdatedAt: now, Since: now}
+	if prev, err := ReadState(statusDir, sessionName); err == nil && prev.State == sf.State && !prev.Since.IsZero() {
+		sf.Since = prev.Since
+	}
+	if err := os.MkdirAll(statusDir, 0o755); err != nil {
+		return bestState, err
+	}
+	data, err := json.Marshal(sf)
+	if err != nil {
+		return bestState, err
+	}
+	return bestState, atomicfile.Write(stateFilePath(statusDir, sessionName), data)
+}
+
+// ExpireAgentStates removes the stale and unreadable agent files of a
+// session and, if any were removed, recomputes its aggregate. It reports
+// whether anything changed, so a session whose hooks stopped firing doesn't
+// keep its last state forever.
+func ExpireAgentStates(statusDir, sessionName string, staleThreshold time.Duration) (bool, error) {
+	files, err := listAgentStateFiles(statusDir, sessionName)
+	if err != nil {
+		return false, err
+	}
+
+	expired := false
+	for _, f := range files {
+		sf, readErr := readStateFromPath(f)
+		if readErr == nil && !IsStale(sf, staleThreshold) {
+			continue
+		}
+		if err := os.Remove(f); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return expired, err
+		}
+		expired = true
+	}
+
+	if !expired {
+		return false, nil
+	}
+	_, err = UpdateAggregate(statusDir, sessionName, staleThreshold)
+	return true, err
+}
+
+// ListStates reads the aggregate state files in statusDir, keyed by session
+// name. Per-agent and unreadable files are skipped; a missing dir yields no
+// states.
+func ListStates(statusDir string) (map[string]*StateFile, error) {
+	entries, err := os.ReadDir(statusDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return map[string]*StateFile{}, nil
+		}
+		return nil, err
+	}
+
+	states := make(map[string]*StateFile)
+	for _, entry := range entries {
+		name := entry.Name()
+		sessionName, ok := SessionFromFileName(name)
+		if !ok || name != sessionName+".state" {
+			continue
+		}
+		sf, err := readStateFromPath(filepath.Join(statusDir, name))
+		if err != nil {
+			continue
+		}
+		states[sessionName] = sf
+	}
+	return states, nil
+}
+
+// SessionFromFileName returns the session a file in the status dir belongs
+// to, for aggregate ({session}.state), per-agent ({session}.agent.{id}.state)
+// and notification cooldown ({session}.notified) files. ok is false for any
+// other file.
+func SessionFromFileName(name string) (sessionName string, ok bool) {
+	if sessionName, found := strings.CutSuffix(name, ".notified"); found {
+		return sessionName, sessionName != ""
+	}
+	if !strings.HasSuffix(name, ".state") {
+		return "", false
+	}
+	if i := strings.Index(name, ".agent."); i > 0 {
+		return name[:i], true
+	}
+	sessionName = strings.TrimSuffix(name, ".state")
+	return sessionName, sessionName != ""
 }
 
 func stateFilePath(statusDir, sessionName string) string {
@@ -199,33 +306,3 @@ func readStateFromPath(path string) (*StateFile, error) {
 	}
 	return &sf, nil
 }
-
-// atomicWriteJSON atomically writes JSON data to targetPath via temp file + rename.
-func atomicWriteJSON(statusDir, targetPath string, v any) error {
-	if err := os.MkdirAll(statusDir, 0o755); err != nil {
-		return err
-	}
-	data, err := json.Marshal(v)
-	if err != nil {
-		return err
-	}
-	tmpFile, err := os.CreateTemp(statusDir, "*.tmp")
-	if err != nil {
-		return err
-	}
-	tmpPath := tmpFile.Name()
-	if _, err := tmpFile.Write(data); err != nil {
-		tmpFile.Close()    //nolint:errcheck // Best-effort cleanup on write failure
-		os.Remove(tmpPath) //nolint:errcheck // Best-effort cleanup on write failure
-		return err
-	}
-	if err := tmpFile.Close(); err != nil {
-		os.Remove(tmpPath) //nolint:errcheck // Best-effort cleanup on close failure
-		return err
-	}
-	if err := os.Rename(tmpPath, targetPath); err != nil {
-		os.Remove(tmpPath) //nolint:errcheck // Best-effort cleanup on rename failure
-		return err
-	}
-	return nil
-}
@@ -268,6 +268,45 @@ func TestUpdateAggregate_SingleAgent(t *testing.T) {
 	}
 }
 
+func TestUpdateAggregate_SinceKeptUntilStateChanges(t *testing.T) {
+	tmpDir := t.TempDir()
+	sessionName := "my-session"
+
+	update := func(state types.ClaudeState) *StateFile {
+		t.Helper()
+		if err := WriteAgentState(tmpDir, sessionName, "agent-1", state); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := UpdateAggregate(tmpDir, sessionName, DefaultStaleThreshold); err != nil {
+			t.Fatalf("UpdateAggregate failed: %v", err)
+		}
+		sf, err := ReadState(tmpDir, sessionName)
+		if err != nil {
+			t.Fatalf("ReadState (aggregate) failed: %v", err)
+		}
+		return sf
+	}
+
+	first := update(types.ClaudeStateRunning)
+	if first.Since.IsZero() {
+		t.Fatal("expected the aggregate to record when the state was entered")
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	same := update(types.ClaudeStateRunning)
+	if !same.Since.Equal(first.Since) {
+		t.Errorf("since = %v after rewriting the same state, want %v", same.Since, first.Since)
+	}
+	if !same.UpdatedAt.After(first.UpdatedAt) {
+		t.Errorf("updated_at = %v, want it refreshed past %v", same.UpdatedAt, first.UpdatedAt)
+	}
+
+	changed := update(types.ClaudeStateWaitingForInput)
+	if !changed.Since.After(first.Since) {
+		t.Errorf("since = %v after a state change, want it past %v", changed.Since, first.Since)
+	}
+}
+
 func TestUpdateAggregate_HighestPriorityWins(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "status-agg-*")
 	if err != nil {
@@ -510,3 +549,107 @@ func TestWriteAgentState_PathTraversal(t *testing.T) {
 		t.Errorf("State = %q, want %q", sf.State, types.ClaudeStateRunning)
 	}
 }
+
+func TestSessionFromFileName(t *testing.T) {
+	tests := []struct {
+		name        string
+		wantSession string
+		wantOK      bool
+	}{
+		{"my-session.state", "my-session", true},
+		{"my-session.agent.sess-abc.state", "my-session", true},
+		{"my-session.agent._.state", "my-session", true},
+		{"my-session.notified", "my-session", true},
+		{"123456.tmp", "", false},
+		{".state", "", false},
+		{"notes.txt", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := SessionFromFileName(tt.name)
+		if got != tt.wantSession || ok != tt.wantOK {
+			t.Errorf("SessionFromFileName(%q) = (%q, %v), want (%q, %v)", tt.name, got, ok, tt.wantSession, tt.wantOK)
+		}
+	}
+}
+
+func TestListStates(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	if err := WriteState(tmpDir, "s1", types.ClaudeStateWaitingForInput, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteState(tmpDir, "s2", types.ClaudeStateRunning, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteAgentState(tmpDir, "s3", "agent-1", types.ClaudeStateError); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "broken.state"), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	states, err := ListStates(tmpDir)
+	if err != nil {
+		t.Fatalf("ListStates() error = %v", err)
+	}
+	if len(states) != 2 {
+		t.Fatalf("ListStates() returned %d states, want 2 aggregate states: %v", len(states), states)
+	}
+	if states["s1"].State != string(types.ClaudeStateWaitingForInput) || states["s2"].State != string(types.ClaudeStateRunning) {
+		t.Errorf("ListStates() = s1:%q s2:%q", states["s1"].State, states["s2"].State)
+	}
+
+	states, err = ListStates(filepath.Join(tmpDir, "missing"))
+	if err != nil || len(states) != 0 {
+		t.Errorf("ListStates() of a missing dir = %v, %v; want no states", states, err)
+	}
+}
+
+func TestExpireAgentStates(t *testing.T) {
+	tmpDir := t.TempDir()
+	sessionName := "my-session"
+
+	if err := WriteAgentState(tmpDir, sessionName, "fresh-agent", types.ClaudeStateIdle); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing stale: the aggregate must not be rewritten
+	changed, err := ExpireAgentStates(tmpDir, sessionName, DefaultStaleThreshold)
+	if err != nil {
+		t.Fatalf("ExpireAgentStates failed: %v", err)
+	}
+	if changed {
+		t.Error("expected no change without stale agents")
+	}
+	if _, err := ReadState(tmpDir, sessionName); !os.IsNotExist(err) {
+		t.Errorf("expected no aggregate to be written, got err %v", err)
+	}
+
+	staleData, _ := json.Marshal(StateFile{
+		State:     string(types.ClaudeStateWaitingForInput),
+		UpdatedAt: time.Now().Add(-15 * time.Minute),
+	})
+	stalePath := filepath.Join(tmpDir, sessionName+".agent.stale-agent.state")
+	if err := os.WriteFile(stalePath, staleData, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err = ExpireAgentStates(tmpDir, sessionName, DefaultStaleThreshold)
+	if err != nil {
+		t.Fatalf("ExpireAgentStates failed: %v", err)
+	}
+	if !changed {
+		t.Error("expected the stale agent to be expired")
+	}
+	if _, err := os.Stat(stalePath); !os.IsNotExist(err) {
+		t.Error("expected stale agent file to be removed")
+	}
+	sf, err := ReadState(tmpDir, sessionName)
+	if err != nil {
+		t.Fatalf("ReadState (aggregate) failed: %v", err)
+	}
+	if sf.State != string(types.ClaudeStateIdle) {
+		t.Errorf("aggregate = %q, want %q", sf.State, types.ClaudeStateIdle)
+	}
+}
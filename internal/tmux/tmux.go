@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -39,20 +40,144 @@ func (m *Manager) CreateSessionWithCommand(name, path, command string) error {
 	return cmd.Run()
 }
 
-// CreateWindow creates a window in a session
-func (m *Manager) CreateWindow(session, name, command, path string) error {
-	args := []string{"new-window", "-t", session + ":", "-n", name}
+// CreateWindow creates a window in a session, without making it the
+// current window, and returns the window's ID
+func (m *Manager) CreateWindow(session, name, command, path string) (string, error) {
+	args := []string{"new-window", "-d", "-P", "-F", "#{window_id}", "-t", session + ":"}
+	if name != "" {
+		args = append(args, "-n", name)
+	}
 	if path != "" {
 		args = append(args, "-c", path)
 	}
 	if command != "" {
 		args = append(args, command)
 	}
+	output, err := exec.Command("tmux", args...).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// CreateSessionWithWindow creates a new tmux session whose first window is
+// named windowName and returns the window's ID
+func (m *Manager) CreateSessionWithWindow(name, path, windowName, command string) (string, error) {
+	args := []string{"new-session", "-d", "-P", "-F", "#{window_id}", "-s", name, "-c", path}
+	if windowName != "" {
+		args = append(args, "-n", windowName)
+	}
+	if command != "" {
+		args = append(args, command)
+	}
+	output, err := exec.Command("tmux", args...).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
 
+// SplitWindow adds a pane to the target window. With horizontal set the new
+// pane is placed beside the current one instead of below it.
+func (m *Manager) SplitWindow(target, command, path string, horizontal bool) error {
+	args := []string{"split-window", "-d", "-t", target}
+	if horizontal {
+		args = append(args, "-h")
+	} else {
+		args = append(args, "-v")
+	}
+	if path != "" {
+		args = append(args, "-c", path)
+	}
+	if command != "" {
+		args = append(args, command)
+	}
 	cmd := exec.Command("tmux", args...)
 	return cmd.Run()
 }
 
+// SelectLayout arranges the panes of the target window using a tmux layout
+// name such as "even-horizontal" or "main-vertical"
+func (m *Manager) SelectLayout(target, layout string) error {
+	cmd := exec.Command("tmux", "select-layout", "-t", target, layout)
+	return cmd.Run()
+}
+
+// Pane is a pane of a window along with the value of a user option
+type Pane struct {
+	ID     string
+	Dead   bool // The pane's command exited and the pane was kept
+	Option string
+}
+
+// ListPanes returns the panes of the target window with the value of the
+// pane option named option, e.g. "@role"
+func (m *Manager) ListPanes(target, option string) ([]Pane, error) {
+	cmd := exec.Command("tmux", "list-panes", "-t", target,
+		"-F", "#{pane_id}\t#{pane_dead}\t#{"+option+"}")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return parsePanes(string(output)), nil
+}
+
+func parsePanes(output string) []Pane {
+	var panes []Pane
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		panes = append(panes, Pane{ID: fields[0], Dead: fields[1] == "1", Option: fields[2]})
+	}
+	return panes
+}
+
+// NewPane splits the target window and returns the new pane's ID
+func (m *Manager) NewPane(target, command string) (string, error) {
+	args := []string{"split-window", "-d", "-P", "-F", "#{pane_id}", "-t", target}
+	if command != "" {
+		args = append(args, command)
+	}
+	output, err := exec.Command("tmux", args...).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// RespawnPane replaces whatever runs in a pane with command
+func (m *Manager) RespawnPane(paneID, command string) error {
+	cmd := exec.Command("tmux", "respawn-pane", "-k", "-t", paneID, command)
+	return cmd.Run()
+}
+
+// KillPane closes a pane
+func (m *Manager) KillPane(paneID string) error {
+	cmd := exec.Command("tmux", "kill-pane", "-t", paneID)
+	return cmd.Run()
+}
+
+// SetPaneOption sets a pane-level option such as a user "@" option
+func (m *Manager) SetPaneOption(paneID, option, value string) error {
+	cmd := exec.Command("tmux", "set-option", "-p", "-t", paneID, option, value)
+	return cmd.Run()
+}
+
+// SetWindowOption sets a window-level option
+func (m *Manager) SetWindowOption(target, option, value string) error {
+	cmd := exec.Command("tmux", "set-option", "-w", "-t", target, option, value)
+	return cmd.Run()
+}
+
+// RunShellBackground runs a shell command in the background from the tmux
+// server, so it outlives the calling process
+func (m *Manager) RunShellBackground(command string) error {
+	cmd := exec.Command("tmux", "run-shell", "-b", command)
+	return cmd.Run()
+}
+
 // SessionExists checks if a tmux session exists
 func (m *Manager) SessionExists(name string) bool {
 	cmd := exec.Command("tmux", "has-session", "-t", name)
@@ -80,6 +205,48 @@ func (m *Manager) SwitchToSession(name string) error {
 	return cmd.Run()
 }
 
+// DisplayMessage shows a message in the tmux status line of the current client
+func (m *Manager) DisplayMessage(msg string) error {
+	cmd := exec.Command("tmux", "display-message", msg)
+	return cmd.Run()
+}
+
+// ListClients returns the name and tty of every attached client
+func (m *Manager) ListClients() (names, ttys []string, err error) {
+	cmd := exec.Command("tmux", "list-clients", "-F", "#{client_name}\t#{client_tty}")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		name, tty, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		names = append(names, name)
+		ttys = append(ttys, tty)
+	}
+	return names, ttys, nil
+}
+
+// DisplayMessageAll shows a message in the status line of every attached client
+func (m *Manager) DisplayMessageAll(msg string) error {
+	clients, _, err := m.ListClients()
+	if err != nil {
+		return err
+	}
+
+	var firstErr error
+	for _, client := range clients {
+		cmd := exec.Command("tmux", "display-message", "-c", client, msg)
+		if err := cmd.Run(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
 // SetSessionEnv sets a session-level environment variable
 func (m *Manager) SetSessionEnv(session, key, value string) error {
 	cmd := exec.Command("tmux", "set-environment", "-t", session, key, value)
@@ -122,8 +289,54 @@ func (m *Manager) GetClaudeStatus(session string) bool {
 	return false
 }
 
+// FindClaudePane returns the ID of the pane running Claude in any window of
+// session. ok is false when Claude isn't running in the session or its panes
+// could not be listed.
+func (m *Manager) FindClaudePane(session string) (paneID string, ok bool) {
+	paneID, ok, _ = m.LookupClaudePane(session)
+	return paneID, ok
+}
+
+// LookupClaudePane is FindClaudePane reporting tmux failures, so a failed
+// lookup can be told apart from Claude not running
+func (m *Manager) LookupClaudePane(session string) (paneID string, ok bool, err error) {
+	cmd := exec.Command("tmux", "list-panes", "-s", "-t", session,
+		"-F", "#{pane_id}\t#{pane_pid}\t#{pane_current_command}")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to list panes of %s: %w", session, err)
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		// Claude is either the pane's own process or a child of its shell
+		if isClaudeProcess(fields[2]) || m.childProcessMatches(fields[1], isClaudeProcess) {
+			return fields[0], true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// isClaudeProcess reports whether a process name, as tmux or ps print it, is
+// the Claude binary. The name must match exactly, so a pane running
+// claude-matrix itself isn't taken for Claude's pane.
+func isClaudeProcess(name string) bool {
+	return filepath.Base(strings.TrimSpace(name)) == "claude"
+}
+
 // processIsClaude checks if a PID is running Claude
 func (m *Manager) processIsClaude(pid string) bool {
+	return m.childProcessMatches(pid, func(name string) bool {
+		return strings.Contains(name, "claude")
+	})
+}
+
+// childProcessMatches reports whether match accepts the name of a child
+// process of pid
+func (m *Manager) childProcessMatches(pid string, match func(name string) bool) bool {
 	if pid == "" {
 		return false
 	}
@@ -149,7 +362,7 @@ func (m *Manager) processIsClaude(pid string) bool {
 		}
 
 		processName := strings.TrimSpace(string(psOutput))
-		if strings.Contains(processName, "claude") {
+		if match(processName) {
 			return true
 		}
 	}
@@ -221,30 +434,14 @@ func (m *Manager) GetDetailedClaudeState(session string) (types.ClaudeState, tim
 	// Fall back to process-based detection
 
 	// First check if Claude window exists
-	cmd := exec.Command("tmux", "list-windows", "-t", session, "-F", "#{window_name}")
-	output, err := cmd.Output()
-	if err != nil {
-		return types.ClaudeStateStopped, time.Time{}
-	}
-
-	hasClaudeWindow := false
-	claudeWindowName := ""
-	windows := strings.Split(strings.TrimSpace(string(output)), "\n")
-	for _, w := range windows {
-		if stripEmojiPrefix(w) == "claude" {
-			hasClaudeWindow = true
-			claudeWindowName = w
-			break
-		}
-	}
-
+	claudeWindowName, hasClaudeWindow := m.claudeWindow(session)
 	if !hasClaudeWindow {
 		return types.ClaudeStateStopped, time.Time{}
 	}
 
 	// Get pane PID
-	cmd = exec.Command("tmux", "list-panes", "-t", session+":"+claudeWindowName, "-F", "#{pane_pid}")
-	output, err = cmd.Output()
+	cmd := exec.Command("tmux", "list-panes", "-t", session+":"+claudeWindowName, "-F", "#{pane_pid}")
+	output, err := cmd.Output()
 	if err != nil {
 		return types.ClaudeStateStopped, time.Time{}
 	}
@@ -282,9 +479,64 @@ func (m *Manager) GetDetailedClaudeState(session string) (types.ClaudeState, tim
 	return state, lastActivity
 }
 
-// capturePaneContent captures the last N lines from a pane
+// claudeWindow returns the name of the session's Claude window, which may
+// carry a status emoji prefix
+func (m *Manager) claudeWindow(session string) (string, bool) {
+	cmd := exec.Command("tmux", "list-windows", "-t", session, "-F", "#{window_name}")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", false
+	}
+
+	for _, w := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if stripEmojiPrefix(w) == "claude" {
+			return w, true
+		}
+	}
+	return "", false
+}
+
+// CaptureClaudePane returns the last lines of output of the pane running
+// Claude, the one FindClaudePane returns. Without one it captures the active
+// pane of the Claude window, or of the current window when there is no
+// Claude window.
+func (m *Manager) CaptureClaudePane(session string, lines int) (string, error) {
+	var content string
+	var err error
+	if paneID, ok := m.FindClaudePane(session); ok {
+		content, err = m.capturePane(paneID, lines)
+	} else {
+		// Without a Claude window the empty name targets the current window
+		window, _ := m.claudeWindow(session)
+		content, err = m.capturePaneContent(session, window, lines)
+	}
+	if err != nil {
+		return "", err
+	}
+	return lastLines(content, lines), nil
+}
+
+// lastLines returns the last n lines of content, ignoring the blank lines
+// below the cursor that capture-pane includes
+func lastLines(content string, n int) string {
+	trimmed := strings.TrimRight(content, " \n")
+	if trimmed == "" || n <= 0 {
+		return ""
+	}
+	all := strings.Split(trimmed, "\n")
+	if len(all) > n {
+		all = all[len(all)-n:]
+	}
+	return strings.Join(all, "\n") + "\n"
+}
+
+// capturePaneContent captures the last N lines from the active pane of a window
 func (m *Manager) capturePaneContent(session, window string, lines int) (string, error) {
-	target := fmt.Sprintf("%s:%s", session, window)
+	return m.capturePane(fmt.Sprintf("%s:%s", session, window), lines)
+}
+
+// capturePane captures the last N lines from the pane target resolves to
+func (m *Manager) capturePane(target string, lines int) (string, error) {
 	cmd := exec.Command("tmux", "capture-pane", "-t", target, "-p", "-S", fmt.Sprintf("-%d", lines))
 	output, err := cmd.Output()
 	if err != nil {
@@ -434,3 +686,38 @@ func (m *Manager) RenameWindowByPane(paneID, newName string) error {
 	cmd := exec.Command("tmux", "rename-window", "-t", paneID, newName)
 	return cmd.Run()
 }
+
+// pasteDelay gives the program in a pane time to take in a paste before
+// Enter arrives, so the Enter isn't read as part of the pasted text
+const pasteDelay = 200 * time.Millisecond
+
+// SendText types text into a pane as a bracketed paste, so multi-line text
+// arrives as one input instead of line by line, and presses Enter afterwards
+// when enter is set
+func (m *Manager) SendText(paneID, text string, enter bool) error {
+	buffer := fmt.Sprintf("claude-matrix-send-%d", os.Getpid())
+	load := exec.Command("tmux", "load-buffer", "-b", buffer, "-")
+	load.Stdin = strings.NewReader(text)
+	if err := load.Run(); err != nil {
+		return fmt.Errorf("failed to load text: %w", err)
+	}
+	// -p pastes in bracketed mode when the program asked for it, -r keeps
+	// newlines as they are and -d deletes the buffer afterwards
+	if err := exec.Command("tmux", "paste-buffer", "-p", "-r", "-d", "-b", buffer, "-t", paneID).Run(); err != nil {
+		return fmt.Errorf("failed to paste text: %w", err)
+	}
+	if !enter {
+		return nil
+	}
+	time.Sleep(pasteDelay)
+	if err := exec.Command("tmux", "send-keys", "-t", paneID, "Enter").Run(); err != nil {
+		return fmt.Errorf("failed to press Enter: %w", err)
+	}
+	return nil
+}
+
+// ShellQuote single-quotes s so the shell passes it as one literal argument,
+// for the shell commands tmux, FZF and the session panes run
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "'\\''") + "'"
+}
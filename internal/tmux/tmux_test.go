@@ -1,6 +1,7 @@
 package tmux
 
 import (
+	"os/exec"
 	"testing"
 
 	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
@@ -121,3 +122,85 @@ func TestAnalyzeClaudeState(t *testing.T) {
 		})
 	}
 }
+
+func TestLastLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		n       int
+		want    string
+	}{
+		{name: "fewer lines than n", content: "a\nb\n", n: 5, want: "a\nb\n"},
+		{name: "keeps the last n", content: "a\nb\nc\nd\n", n: 2, want: "c\nd\n"},
+		{name: "ignores blank screen below the cursor", content: "a\nb\nc\n\n\n   \n\n", n: 2, want: "b\nc\n"},
+		{name: "empty pane", content: "\n\n", n: 3, want: ""},
+		{name: "zero lines", content: "a\n", n: 0, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lastLines(tt.content, tt.n); got != tt.want {
+				t.Errorf("lastLines(%q, %d) = %q, want %q", tt.content, tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePanes(t *testing.T) {
+	output := "%1\t0\tapi\n%2\t1\t\nbroken line\n"
+	got := parsePanes(output)
+	want := []Pane{
+		{ID: "%1", Option: "api"},
+		{ID: "%2", Dead: true},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parsePanes() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("pane %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	inputs := []string{
+		"simple",
+		"it's a test",
+		`$(rm -rf /) and "quotes" and $HOME`,
+		"multi\nline\nprompt",
+		"'''",
+	}
+
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			out, err := exec.Command("sh", "-c", "printf '%s' "+ShellQuote(input)).Output()
+			if err != nil {
+				t.Fatalf("sh failed: %v", err)
+			}
+			if string(out) != input {
+				t.Errorf("shell round trip = %q, want %q", out, input)
+			}
+		})
+	}
+}
+
+func TestIsClaudeProcess(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"claude", true},
+		{"/usr/local/bin/claude", true}, // ps on macOS prints the path
+		{"claude-matrix", false},
+		{"/home/u/go/bin/claude-matrix", false},
+		{"zsh", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := isClaudeProcess(tt.name); got != tt.want {
+			t.Errorf("isClaudeProcess(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
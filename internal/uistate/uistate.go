@@ -0,0 +1,41 @@
+package uistate
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/mateimicu/tmux-claude-matrix/internal/atomicfile"
+)
+
+// State is the list view state remembered between invocations
+type State struct {
+	SortMode string `json:"sort_mode,omitempty"`
+}
+
+// Load reads the state file at path. A missing or unreadable file yields the
+// zero State, since losing UI preferences is harmless.
+func Load(path string) *State {
+	s := &State{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return s
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return &State{}
+	}
+	return s
+}
+
+// Save atomically writes s to path, creating its directory if needed
+func Save(path string, s *State) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return atomicfile.Write(path, data)
+}
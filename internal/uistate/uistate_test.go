@@ -0,0 +1,29 @@
+package uistate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "ui-state.json")
+
+	if s := Load(path); s.SortMode != "" {
+		t.Errorf("Load() of a missing file = %+v, want zero state", s)
+	}
+
+	if err := Save(path, &State{SortMode: "attention"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if s := Load(path); s.SortMode != "attention" {
+		t.Errorf("Load() SortMode = %q, want %q", s.SortMode, "attention")
+	}
+
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if s := Load(path); s.SortMode != "" {
+		t.Errorf("Load() of a corrupt file = %+v, want zero state", s)
+	}
+}
@@ -0,0 +1,193 @@
+// Package client queries the claude-matrix daemon for the state of all
+// sessions over its Unix socket.
+//
+// The protocol is line-delimited JSON: the client sends one Request and the
+// daemon answers with one Response. After a subscribe request is
+// acknowledged, the daemon keeps writing one Event per line until the
+// connection is closed.
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+// Methods understood by the daemon
+const (
+	MethodList      = "list"
+	MethodGet       = "get"
+	MethodSubscribe = "subscribe"
+)
+
+// Request is one line of JSON sent to the daemon
+type Request struct {
+	Method  string `json:"method"`
+	Session string `json:"session,omitempty"` // Session name for MethodGet
+}
+
+// Response is the daemon's answer to a Request
+type Response struct {
+	Sessions []*types.SessionStatus `json:"sessions,omitempty"` // MethodList
+	// Unmanaged lists, for MethodList, the live tmux sessions without
+	// metadata that have an aggregate state
+	Unmanaged []*types.SessionStatus `json:"unmanaged,omitempty"`
+	Session   *types.SessionStatus   `json:"session,omitempty"` // MethodGet, nil if there is no such session
+	Error     string                 `json:"error,omitempty"`
+}
+
+// Event reports a session whose Claude state changed. A session that
+// appeared has an empty OldState; one that was deleted has an empty
+// NewState and no Status.
+type Event struct {
+	Session   string               `json:"session"`
+	OldState  types.ClaudeState    `json:"old_state,omitempty"`
+	NewState  types.ClaudeState    `json:"new_state,omitempty"`
+	Status    *types.SessionStatus `json:"status,omitempty"`
+	Timestamp time.Time            `json:"timestamp"`
+}
+
+// ErrNotFound is returned by Get for a session the daemon doesn't know
+var ErrNotFound = errors.New("session not found")
+
+const (
+	// DialTimeout bounds how long a client waits for a daemon that isn't
+	// there, so callers can quickly fall back to reading state themselves
+	DialTimeout = 200 * time.Millisecond
+	// DefaultTimeout bounds a list or get exchange
+	DefaultTimeout = 2 * time.Second
+)
+
+// DefaultSocketPath returns the socket the daemon listens on unless
+// DAEMON_SOCKET says otherwise
+func DefaultSocketPath() string {
+	return filepath.Join(os.Getenv("HOME"), ".tmux-claude-matrix/daemon.sock")
+}
+
+// Client talks to the daemon listening on SocketPath
+type Client struct {
+	SocketPath string
+	Timeout    time.Duration // Bounds list and get; zero means DefaultTimeout
+}
+
+// New returns a client for the daemon listening on socketPath
+func New(socketPath string) *Client {
+	return &Client{SocketPath: socketPath, Timeout: DefaultTimeout}
+}
+
+// List returns the status of every session the daemon knows
+func (c *Client) List(ctx context.Context) ([]*types.SessionStatus, error) {
+	sessions, _, err := c.ListAll(ctx)
+	return sessions, err
+}
+
+// ListAll returns the status of every session the daemon knows and of the
+// live unmanaged sessions that have an aggregate state
+func (c *Client) ListAll(ctx context.Context) (sessions, unmanaged []*types.SessionStatus, err error) {
+	resp, err := c.call(ctx, &Request{Method: MethodList})
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.Sessions, resp.Unmanaged, nil
+}
+
+// Get returns the status of one session, or ErrNotFound
+func (c *Client) Get(ctx context.Context, name string) (*types.SessionStatus, error) {
+	resp, err := c.call(ctx, &Request{Method: MethodGet, Session: name})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Session == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return resp.Session, nil
+}
+
+// Subscribe calls handler with every state change until ctx is cancelled,
+// which returns nil, or the connection to the daemon is lost
+func (c *Client) Subscribe(ctx context.Context, handler func(*Event)) error {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Closing the connection unblocks the read loop on cancellation
+	stop := context.AfterFunc(ctx, func() { conn.Close() }) //nolint:errcheck // Only unblocking reads
+	defer stop()
+
+	reader := bufio.NewReader(conn)
+	if _, err := exchange(conn, reader, &Request{Method: MethodSubscribe}); err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(reader)
+	for {
+		var ev Event
+		if err := dec.Decode(&ev); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("subscription ended: %w", err)
+		}
+		handler(&ev)
+	}
+}
+
+// call makes a single request/response exchange
+func (c *Client) call(ctx context.Context, req *Request) (*Response, error) {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline) //nolint:errcheck // The exchange fails on its own without it
+
+	return exchange(conn, bufio.NewReader(conn), req)
+}
+
+func (c *Client) dial(ctx context.Context) (net.Conn, error) {
+	dialer := net.Dialer{Timeout: DialTimeout}
+	conn, err := dialer.DialContext(ctx, "unix", c.SocketPath)
+	if err != nil {
+		return nil, fmt.Errorf("daemon not reachable: %w", err)
+	}
+	return conn, nil
+}
+
+// exchange writes req and reads the daemon's response
+func exchange(conn net.Conn, reader *bufio.Reader, req *Request) (*Response, error) {
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, err
+	}
+
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		return nil, fmt.Errorf("no response from daemon: %w", err)
+	}
+	var resp Response
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return nil, fmt.Errorf("bad daemon response: %w", err)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("daemon: %s", resp.Error)
+	}
+	return &resp, nil
+}
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mateimicu/tmux-claude-matrix/pkg/types"
+)
+
+// fakeDaemon answers every request on a fresh socket with respond
+func fakeDaemon(t *testing.T, respond func(*Request) *Response) *Client {
+	t.Helper()
+	socketPath := filepath.Join(t.TempDir(), "daemon.sock")
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			line, err := bufio.NewReader(conn).ReadBytes('\n')
+			var req Request
+			if err == nil && json.Unmarshal(line, &req) == nil {
+				json.NewEncoder(conn).Encode(respond(&req)) //nolint:errcheck // Test server
+			}
+			conn.Close()
+		}
+	}()
+	return New(socketPath)
+}
+
+func TestClientRequests(t *testing.T) {
+	c := fakeDaemon(t, func(req *Request) *Response {
+		switch req.Method {
+		case MethodList:
+			return &Response{Sessions: []*types.SessionStatus{{Session: &types.Session{Name: "a"}}}}
+		case MethodGet:
+			if req.Session == "a" {
+				return &Response{Session: &types.SessionStatus{Session: &types.Session{Name: "a"}}}
+			}
+			return &Response{}
+		default:
+			return &Response{Error: "unknown method"}
+		}
+	})
+	ctx := context.Background()
+
+	sessions, err := c.List(ctx)
+	if err != nil || len(sessions) != 1 || sessions[0].Session.Name != "a" {
+		t.Errorf("List() = %v, %v", sessions, err)
+	}
+	if st, err := c.Get(ctx, "a"); err != nil || st.Session.Name != "a" {
+		t.Errorf("Get(a) = %v, %v", st, err)
+	}
+	if _, err := c.Get(ctx, "b"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(b) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestClientDaemonError(t *testing.T) {
+	c := fakeDaemon(t, func(*Request) *Response { return &Response{Error: "boom"} })
+	if _, err := c.List(context.Background()); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("List() error = %v, want the daemon's error", err)
+	}
+}
+
+func TestClientNoDaemon(t *testing.T) {
+	c := New(filepath.Join(t.TempDir(), "missing.sock"))
+	if _, err := c.List(context.Background()); err == nil {
+		t.Error("expected List to fail without a daemon")
+	}
+	if err := c.Subscribe(context.Background(), func(*Event) {}); err == nil {
+		t.Error("expected Subscribe to fail without a daemon")
+	}
+}
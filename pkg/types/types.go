@@ -20,6 +20,19 @@ type Session struct {
 	RepoURL   string    `json:"repo_url"`
 	ClonePath string    `json:"clone_path"`
 	RepoURLs  []string  `json:"repo_urls,omitempty"` // Multiple repos for workspaces
+	Branch    string    `json:"branch,omitempty"`    // Branch the session works on
+	Prompt    string    `json:"prompt,omitempty"`    // Initial task given to Claude
+	Layout    string    `json:"layout,omitempty"`    // Window layout from layouts.yaml
+	Tags      []string  `json:"tags,omitempty"`      // Free-form labels for filtering
+	// ClaudeSessionID is the last Claude conversation seen in the session,
+	// used to resume it when the tmux session is recreated.
+	ClaudeSessionID string `json:"claude_session_id,omitempty"`
+	// MirrorPath is set for worktree-backed sessions to the mirror the
+	// worktree at ClonePath was added to.
+	MirrorPath string `json:"mirror_path,omitempty"`
+	// SchemaVersion is the version of the on-disk format. Older files are
+	// upgraded by the session package's migrations when they are loaded.
+	SchemaVersion int `json:"schema_version"`
 }
 
 // ClaudeState represents the detailed state of a Claude process
@@ -42,11 +55,16 @@ const (
 
 // SessionStatus represents runtime session information
 type SessionStatus struct {
-	Session       *Session
-	TmuxActive    bool
-	ClaudeRunning bool
-	ClaudeState   ClaudeState
-	LastActivity  time.Time
+	Session       *Session    `json:"session"`
+	TmuxActive    bool        `json:"tmux_active"`
+	ClaudeRunning bool        `json:"claude_running"`
+	ClaudeState   ClaudeState `json:"claude_state"`
+	LastActivity  time.Time   `json:"last_activity"`
+	// AggregateState is the state in the session's aggregate state file, as
+	// written by the hooks, and StateSince when it was entered. Both are
+	// empty without a state file.
+	AggregateState ClaudeState `json:"aggregate_state,omitempty"`
+	StateSince     time.Time   `json:"state_since,omitempty"`
 }
 
 // Config represents plugin configuration
@@ -54,14 +72,29 @@ type Config struct {
 	CloneDir           string
 	LocalReposFile     string
 	WorkspacesFile     string
+	LayoutsFile        string
+	UIStateFile        string // List view preferences remembered between invocations
+	DaemonSocket       string // Unix socket of the state daemon
 	ClaudeBin          string
+	BranchPattern      string
 	CacheDir           string
 	SessionsDir        string
+	ArchiveDir         string
+	PreviewWindow      string // FZF --preview-window spec of the session picker
+	PreviewScrollUp    string // Key scrolling the session preview up
+	PreviewScrollDown  string // Key scrolling the session preview down
+	NotifyCommand      string // Shell command of the "command" notify backend
+	NotifyWebhookURL   string // Endpoint the "webhook" notify backend POSTs to
+	NotifyWebhookKey   string // HMAC key signing webhook payloads, empty disables signing
 	GitHubOrgs         []string
 	ClaudeArgs         []string
+	NotifyRules        map[string][]string // Claude state -> notify backends
 	CacheTTL           time.Duration
+	NotifyCooldown     time.Duration // Minimum time between notifications of a session about the same state
+	PreviewLines       int           // Claude pane lines shown in the session preview, 0 disables
 	GitHubEnabled      bool
 	LocalConfigEnabled bool
 	WorkspacesEnabled  bool
+	UseWorktrees       bool
 	Debug              bool
 }